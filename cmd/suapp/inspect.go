@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/flashbots/suapp-examples/framework"
+)
+
+func inspect(args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	artifactsDir := flags.String("artifacts", "", "directory with the compiled artifacts (default: "+framework.ArtifactsDirEnv+" or the Foundry 'out' folder)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: suapp inspect [flags] <hash|rawhex>\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	input := flags.Arg(0)
+
+	ctx := context.Background()
+
+	artifacts, err := framework.LoadArtifacts(*artifactsDir)
+	if err != nil {
+		return err
+	}
+
+	// inspecting only reads from the node, it needs neither an account nor
+	// a kettle, so the framework is not created
+	config, err := framework.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	url := config.KettleRPC
+	if len(config.KettleRPCs) > 0 {
+		url = config.KettleRPCs[0]
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return &framework.DialError{URL: url, Err: err}
+	}
+	defer client.Close()
+	inspector := framework.NewClientInspector(client, artifacts)
+
+	var res *framework.Inspection
+	data, err := hexutil.Decode(strings.TrimSpace(input))
+	switch {
+	case err != nil:
+		return fmt.Errorf("'%s' is neither a hash nor a raw transaction: %w", input, err)
+	case len(data) == common.HashLength:
+		res, err = inspector.InspectTx(ctx, common.BytesToHash(data))
+	default:
+		res, err = inspector.InspectRaw(ctx, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	printInspection(os.Stdout, res)
+	return nil
+}
+
+func printInspection(w io.Writer, res *framework.Inspection) {
+	txn := res.Tx
+	fmt.Fprintf(w, "Transaction  %s\n", txn.Hash().Hex())
+	fmt.Fprintf(w, "Type         %s\n", txTypeName(txn.Type()))
+	if res.SignatureErr != nil {
+		fmt.Fprintf(w, "Signature    INVALID: %v\n", res.SignatureErr)
+	} else {
+		fmt.Fprintf(w, "Signature    valid, signed by %s\n", res.Sender.Hex())
+	}
+
+	if req := res.Request; req != nil {
+		fmt.Fprintf(w, "\nConfidential request\n")
+		fmt.Fprintf(w, "  Kettle        %s\n", req.KettleAddress.Hex())
+		if req.To != nil {
+			fmt.Fprintf(w, "  To            %s\n", req.To.Hex())
+		}
+		fmt.Fprintf(w, "  Nonce         %d\n", req.Nonce)
+		fmt.Fprintf(w, "  Gas           %d\n", req.Gas)
+		fmt.Fprintf(w, "  Gas price     %s\n", req.GasPrice)
+		if req.Value != nil && req.Value.Sign() != 0 {
+			fmt.Fprintf(w, "  Value         %s\n", req.Value)
+		}
+		fmt.Fprintf(w, "  EIP-712       %t\n", req.IsEIP712)
+		fmt.Fprintf(w, "  Inputs hash   %s\n", req.ConfidentialInputsHash.Hex())
+		if res.ConfidentialInputs != nil {
+			fmt.Fprintf(w, "  Inputs        %s\n", hexutil.Encode(res.ConfidentialInputs))
+		}
+	} else if txn.To() != nil {
+		fmt.Fprintf(w, "To           %s\n", txn.To().Hex())
+	}
+
+	fmt.Fprintf(w, "\nCall\n")
+	if res.Method != nil {
+		fmt.Fprintf(w, "  Contract  %s\n", res.Contract)
+		printMethod(w, res.Method, res.Args)
+	} else {
+		calldata := txn.Data()
+		if res.Request != nil {
+			calldata = res.Request.Data
+		}
+		fmt.Fprintf(w, "  unknown method, calldata %s\n", hexutil.Encode(calldata))
+	}
+
+	if txn.Type() == types.SuaveTxType {
+		fmt.Fprintf(w, "\nCallback\n")
+		if res.CallbackMethod != nil {
+			printMethod(w, res.CallbackMethod, res.CallbackArgs)
+		} else {
+			fmt.Fprintf(w, "  unknown method, calldata %s\n", hexutil.Encode(res.Callback))
+		}
+	}
+
+	fmt.Fprintf(w, "\nReceipt\n")
+	if txn.Type() == types.ConfidentialComputeRequestTxType {
+		fmt.Fprintf(w, "  none, a confidential request is not included itself\n")
+		fmt.Fprintf(w, "  inspect the hash of its SUAVE transaction, returned by the kettle when it is sent\n")
+	} else if res.Receipt == nil {
+		fmt.Fprintf(w, "  not included\n")
+	} else {
+		status := "success"
+		if res.Receipt.Status == types.ReceiptStatusFailed {
+			status = "failed"
+		}
+		fmt.Fprintf(w, "  Status    %s\n", status)
+		fmt.Fprintf(w, "  Block     %s\n", res.Receipt.BlockNumber)
+		fmt.Fprintf(w, "  Gas used  %d\n", res.Receipt.GasUsed)
+		fmt.Fprintf(w, "  Logs      %d\n", len(res.Receipt.Logs))
+	}
+
+	if len(res.Events) > 0 {
+		fmt.Fprintf(w, "\nEvents\n")
+		for _, event := range res.Events {
+			where := "on-chain"
+			if event.Offchain {
+				where = "off-chain"
+			}
+			fmt.Fprintf(w, "  %s (%s, %s)\n", event.Name, where, event.Log.Address.Hex())
+			names := make([]string, 0, len(event.Args))
+			for name := range event.Args {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(w, "    %s: %s\n", name, formatValue(event.Args[name]))
+			}
+		}
+	}
+}
+
+func printMethod(w io.Writer, method *abi.Method, args []interface{}) {
+	fmt.Fprintf(w, "  Method    %s\n", method.Sig)
+	for i, arg := range method.Inputs {
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		fmt.Fprintf(w, "    %s %s: %s\n", arg.Type.String(), name, formatValue(args[i]))
+	}
+}
+
+func txTypeName(typ uint8) string {
+	switch typ {
+	case types.LegacyTxType:
+		return "legacy"
+	case types.AccessListTxType:
+		return "access list (EIP-2930)"
+	case types.DynamicFeeTxType:
+		return "dynamic fee (EIP-1559)"
+	case types.ConfidentialComputeRequestTxType:
+		return "confidential compute request"
+	case types.SuaveTxType:
+		return "SUAVE transaction"
+	}
+	return fmt.Sprintf("%d", typ)
+}
+
+// formatValue prints the byte arrays and slices of the decoded values in hex.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case common.Address:
+		return v.Hex()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = formatValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		fields := make([]string, rv.NumField())
+		for i := range fields {
+			fields[i] = rv.Type().Field(i).Name + ": " + formatValue(rv.Field(i).Interface())
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,38 @@
+// suapp is a command line tool for the Suapps built with the framework.
+//
+// Usage:
+//
+//	suapp inspect [-artifacts out] <hash|rawhex>
+//
+// The SUAVE node is the one of the framework configuration, KETTLE_RPC.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var commands = map[string]func(args []string) error{
+	"inspect": inspect,
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: suapp <command> [flags] [args]\n\nCommands:\n")
+	fmt.Fprintf(os.Stderr, "  inspect    decode a SUAVE transaction, confidential request and receipt\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "suapp %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
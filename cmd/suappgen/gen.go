@@ -0,0 +1,512 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/flashbots/suapp-examples/framework"
+)
+
+type generator struct {
+	pkg       string
+	contracts []*contractModel
+
+	// structs are shared by all the contracts of the package
+	structs     map[string]*structModel
+	structNames map[string]string
+}
+
+type contractModel struct {
+	Type         string
+	Path         string
+	Constructor  []argModel
+	Confidential []*methodModel
+	Views        []*methodModel
+	Events       []*eventModel
+	Errors       []*errorModel
+}
+
+type argModel struct {
+	Name string
+	Type string
+}
+
+type methodModel struct {
+	Name    string
+	GoName  string
+	Sig     string
+	Inputs  []argModel
+	Outputs []argModel
+}
+
+type eventModel struct {
+	Name   string
+	GoName string
+	Sig    string
+	Fields []argModel
+}
+
+type errorModel struct {
+	Name     string
+	GoName   string
+	Sig      string
+	Selector string
+	Fields   []argModel
+}
+
+type structModel struct {
+	Name   string
+	Fields []argModel
+}
+
+func newGenerator(pkg string) *generator {
+	return &generator{
+		pkg:         pkg,
+		structs:     map[string]*structModel{},
+		structNames: map[string]string{},
+	}
+}
+
+// contractName returns the contract name of an artifact path, either
+// the '<Name>' in '<file>.sol/<Name>.json' or the ':<Name>' selector.
+func contractName(artifactPath string) string {
+	if idx := strings.LastIndex(artifactPath, ":"); idx != -1 {
+		return artifactPath[idx+1:]
+	}
+	return strings.TrimSuffix(path.Base(artifactPath), ".json")
+}
+
+func (g *generator) addContract(typeName, artifactPath string, artifact *framework.Artifact) error {
+	contract := &contractModel{
+		Type: abi.ToCamelCase(typeName),
+		Path: artifactPath,
+	}
+	contractAbi := artifact.Abi
+
+	var err error
+	if contract.Constructor, err = g.args(contractAbi.Constructor.Inputs, false); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(contractAbi.Methods) {
+		method := contractAbi.Methods[name]
+
+		inputs, err := g.args(method.Inputs, false)
+		if err != nil {
+			return err
+		}
+		outputs, err := g.args(method.Outputs, true)
+		if err != nil {
+			return err
+		}
+		model := &methodModel{
+			Name:    name,
+			GoName:  abi.ToCamelCase(name),
+			Sig:     method.Sig,
+			Inputs:  inputs,
+			Outputs: outputs,
+		}
+
+		switch {
+		case method.IsConstant():
+			contract.Views = append(contract.Views, model)
+		case isConfidential(method):
+			contract.Confidential = append(contract.Confidential, model)
+		}
+		// the rest are the onchain callbacks of the confidential requests
+	}
+
+	for _, name := range sortedKeys(contractAbi.Events) {
+		event := contractAbi.Events[name]
+
+		fields, err := g.eventFields(event.Inputs)
+		if err != nil {
+			return err
+		}
+		contract.Events = append(contract.Events, &eventModel{
+			Name:   name,
+			GoName: abi.ToCamelCase(name),
+			Sig:    event.Sig,
+			Fields: fields,
+		})
+	}
+
+	for _, name := range sortedKeys(contractAbi.Errors) {
+		abiErr := contractAbi.Errors[name]
+
+		fields, err := g.args(abiErr.Inputs, true)
+		if err != nil {
+			return err
+		}
+		contract.Errors = append(contract.Errors, &errorModel{
+			Name:     name,
+			GoName:   abi.ToCamelCase(name),
+			Sig:      abiErr.Sig,
+			Selector: fmt.Sprintf("{0x%02x, 0x%02x, 0x%02x, 0x%02x}", abiErr.ID[0], abiErr.ID[1], abiErr.ID[2], abiErr.ID[3]),
+			Fields:   fields,
+		})
+	}
+
+	g.contracts = append(g.contracts, contract)
+	return nil
+}
+
+// isConfidential returns whether the method is a confidential request, that is,
+// it returns the calldata of the onchain callback as bytes.
+func isConfidential(method abi.Method) bool {
+	return len(method.Outputs) == 1 && method.Outputs[0].Type.T == abi.BytesTy
+}
+
+// args returns the Go arguments of an ABI argument list. If fields is set,
+// the names are exported struct field names, otherwise function parameters.
+func (g *generator) args(args abi.Arguments, fields bool) ([]argModel, error) {
+	res := make([]argModel, len(args))
+	for i, arg := range args {
+		typ, err := g.goType(arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = argModel{Name: argName(arg.Name, i, fields), Type: typ}
+	}
+	return res, nil
+}
+
+// eventFields returns the fields of an event. Indexed arguments of a dynamic
+// type are only available as the hash of their value.
+func (g *generator) eventFields(args abi.Arguments) ([]argModel, error) {
+	res := make([]argModel, len(args))
+	for i, arg := range args {
+		typ, err := g.goType(arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		if arg.Indexed && isDynamicTopic(arg.Type) {
+			typ = "common.Hash"
+		}
+		res[i] = argModel{Name: argName(arg.Name, i, true), Type: typ}
+	}
+	return res, nil
+}
+
+func isDynamicTopic(typ abi.Type) bool {
+	switch typ.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
+// reservedNames are the parameter names used by the generated methods.
+var reservedNames = map[string]bool{
+	"ctx":                true,
+	"c":                  true,
+	"chain":              true,
+	"opts":               true,
+	"confidentialInputs": true,
+	"out":                true,
+	"err":                true,
+}
+
+func argName(name string, i int, field bool) string {
+	if name == "" {
+		name = fmt.Sprintf("arg%d", i)
+	}
+	name = abi.ToCamelCase(name)
+	if field {
+		return name
+	}
+
+	name = strings.ToLower(name[:1]) + name[1:]
+	if token.IsKeyword(name) || reservedNames[name] {
+		name += "_"
+	}
+	return name
+}
+
+// goType returns the Go type used to (un)pack an ABI type.
+func (g *generator) goType(typ abi.Type) (string, error) {
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		prefix := "int"
+		if typ.T == abi.UintTy {
+			prefix = "uint"
+		}
+		switch typ.Size {
+		case 8, 16, 32, 64:
+			return fmt.Sprintf("%s%d", prefix, typ.Size), nil
+		}
+		return "*big.Int", nil
+	case abi.BoolTy:
+		return "bool", nil
+	case abi.StringTy:
+		return "string", nil
+	case abi.AddressTy:
+		return "common.Address", nil
+	case abi.BytesTy:
+		return "[]byte", nil
+	case abi.FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", typ.Size), nil
+	case abi.FunctionTy:
+		return "[24]byte", nil
+	case abi.SliceTy:
+		elem, err := g.goType(*typ.Elem)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case abi.ArrayTy:
+		elem, err := g.goType(*typ.Elem)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("[%d]%s", typ.Size, elem), nil
+	case abi.TupleTy:
+		return g.structType(typ)
+	}
+	return "", fmt.Errorf("unsupported abi type %s", typ.String())
+}
+
+// structType declares the struct of a tuple type and returns its name.
+func (g *generator) structType(typ abi.Type) (string, error) {
+	id := typ.TupleRawName + typ.String()
+	if name, ok := g.structNames[id]; ok {
+		return name, nil
+	}
+
+	name := abi.ToCamelCase(typ.TupleRawName)
+	if name == "" {
+		name = "Struct"
+	}
+	if _, ok := g.structs[name]; ok {
+		for i := 0; ; i++ {
+			if _, ok := g.structs[fmt.Sprintf("%s%d", name, i)]; !ok {
+				name = fmt.Sprintf("%s%d", name, i)
+				break
+			}
+		}
+	}
+	g.structNames[id] = name
+
+	model := &structModel{Name: name}
+	g.structs[name] = model
+	for i, elem := range typ.TupleElems {
+		fieldType, err := g.goType(*elem)
+		if err != nil {
+			return "", err
+		}
+		model.Fields = append(model.Fields, argModel{Name: argName(typ.TupleRawNames[i], i, true), Type: fieldType})
+	}
+	return name, nil
+}
+
+func (g *generator) generate() ([]byte, error) {
+	structs := make([]*structModel, 0, len(g.structs))
+	for _, name := range sortedKeys(g.structs) {
+		structs = append(structs, g.structs[name])
+	}
+
+	var buf bytes.Buffer
+	err := bindingsTemplate.Execute(&buf, map[string]interface{}{
+		"Package":   g.pkg,
+		"Contracts": g.contracts,
+		"Structs":   structs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func argNames(args []argModel) string {
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+var bindingsTemplate = template.Must(template.New("bindings").Funcs(template.FuncMap{
+	"argNames": argNames,
+}).Parse(bindingsTmpl))
+
+const bindingsTmpl = `// Code generated by suappgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/flashbots/suapp-examples/framework"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = context.Background
+	_ = errors.As
+	_ = fmt.Errorf
+	_ = big.NewInt
+	_ = abi.ConvertType
+	_ = common.Address{}
+	_ = types.Receipt{}
+)
+{{range .Structs}}
+// {{.Name}} is an auto generated low-level Go binding around a user-defined struct.
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+{{end}}
+{{- range $c := .Contracts}}
+// {{$c.Type}}Artifact is the artifact of the {{$c.Type}} Suapp.
+const {{$c.Type}}Artifact = "{{$c.Path}}"
+
+// {{$c.Type}} is a typed binding of the {{$c.Type}} Suapp.
+type {{$c.Type}} struct {
+	*framework.Contract
+}
+
+// New{{$c.Type}} wraps a deployed {{$c.Type}} contract.
+func New{{$c.Type}}(contract *framework.Contract) *{{$c.Type}} {
+	return &{{$c.Type}}{Contract: contract}
+}
+
+// Deploy{{$c.Type}} deploys the {{$c.Type}} Suapp.
+func Deploy{{$c.Type}}(ctx context.Context, chain *framework.Chain{{range $c.Constructor}}, {{.Name}} {{.Type}}{{end}}, opts ...framework.DeployOption) (*{{$c.Type}}, error) {
+	{{- if $c.Constructor}}
+	opts = append([]framework.DeployOption{framework.WithConstructorArgs({{argNames $c.Constructor}})}, opts...)
+	{{- end}}
+	contract, err := chain.DeployContractE(ctx, {{$c.Type}}Artifact, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return New{{$c.Type}}(contract), nil
+}
+{{range $m := $c.Confidential}}
+// {{$m.GoName}} sends a confidential request to '{{$m.Sig}}'.
+func (c *{{$c.Type}}) {{$m.GoName}}(ctx context.Context{{range $m.Inputs}}, {{.Name}} {{.Type}}{{end}}, confidentialInputs []byte, opts ...framework.RequestOption) (*types.Receipt, error) {
+	return c.SendConfidentialRequestE(ctx, "{{$m.Name}}", []interface{}{ {{- argNames $m.Inputs -}} }, confidentialInputs, opts...)
+}
+{{end}}
+{{- range $m := $c.Views}}
+{{- if gt (len $m.Outputs) 1}}
+// {{$c.Type}}{{$m.GoName}}Output is the output of '{{$m.Sig}}'.
+type {{$c.Type}}{{$m.GoName}}Output struct {
+{{- range $m.Outputs}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+{{end}}
+// {{$m.GoName}} calls the view function '{{$m.Sig}}'.
+func (c *{{$c.Type}}) {{$m.GoName}}(ctx context.Context{{range $m.Inputs}}, {{.Name}} {{.Type}}{{end}}) (
+	{{- if gt (len $m.Outputs) 1}}*{{$c.Type}}{{$m.GoName}}Output, {{else}}{{range $m.Outputs}}{{.Type}}, {{end}}{{end}}error) {
+	out, err := c.CallE(ctx, "{{$m.Name}}", []interface{}{ {{- argNames $m.Inputs -}} })
+	{{- if eq (len $m.Outputs) 0}}
+	_ = out
+	return err
+	{{- else if eq (len $m.Outputs) 1}}
+	if err != nil {
+		return *new({{(index $m.Outputs 0).Type}}), err
+	}
+	return *abi.ConvertType(out[0], new({{(index $m.Outputs 0).Type}})).(*{{(index $m.Outputs 0).Type}}), nil
+	{{- else}}
+	if err != nil {
+		return nil, err
+	}
+	res := new({{$c.Type}}{{$m.GoName}}Output)
+	{{- range $i, $o := $m.Outputs}}
+	res.{{$o.Name}} = *abi.ConvertType(out[{{$i}}], new({{$o.Type}})).(*{{$o.Type}})
+	{{- end}}
+	return res, nil
+	{{- end}}
+}
+{{end}}
+{{- range $e := $c.Events}}
+// {{$c.Type}}{{$e.GoName}} is the '{{$e.Sig}}' event.
+type {{$c.Type}}{{$e.GoName}} struct {
+{{- range $e.Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+	Raw *types.Log
+}
+
+// Parse{{$e.GoName}} decodes a '{{$e.Sig}}' event.
+func (c *{{$c.Type}}) Parse{{$e.GoName}}(log *types.Log) (*{{$c.Type}}{{$e.GoName}}, error) {
+	event := new({{$c.Type}}{{$e.GoName}})
+	if err := c.UnpackLog(event, "{{$e.Name}}", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// Filter{{$e.GoName}} returns the '{{$e.Sig}}' events emitted by the contract in the receipt.
+func (c *{{$c.Type}}) Filter{{$e.GoName}}(receipt *types.Receipt) ([]*{{$c.Type}}{{$e.GoName}}, error) {
+	var events []*{{$c.Type}}{{$e.GoName}}
+	for _, log := range receipt.Logs {
+		if log.Address != c.Raw().Address() || len(log.Topics) == 0 || log.Topics[0] != c.Abi.Events["{{$e.Name}}"].ID {
+			continue
+		}
+		event, err := c.Parse{{$e.GoName}}(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+{{end}}
+{{- range $e := $c.Errors}}
+// {{$c.Type}}{{$e.GoName}} is the '{{$e.Sig}}' custom error.
+type {{$c.Type}}{{$e.GoName}} struct {
+{{- range $e.Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+}
+
+func (e *{{$c.Type}}{{$e.GoName}}) Error() string {
+	return fmt.Sprintf("{{$e.Name}}%+v", *e)
+}
+
+// As{{$c.Type}}{{$e.GoName}} returns the '{{$e.Sig}}' error carried by err, if any.
+func As{{$c.Type}}{{$e.GoName}}(err error) (*{{$c.Type}}{{$e.GoName}}, bool) {
+	var revertErr *framework.RevertError
+	if !errors.As(err, &revertErr) {
+		return nil, false
+	}
+	if revertErr.Inner != nil {
+		revertErr = revertErr.Inner
+	}
+	if revertErr.Selector != [4]byte{{$e.Selector}} || len(revertErr.Args) != {{len $e.Fields}} {
+		return nil, false
+	}
+	res := new({{$c.Type}}{{$e.GoName}})
+	{{- range $i, $f := $e.Fields}}
+	res.{{$f.Name}} = *abi.ConvertType(revertErr.Args[{{$i}}], new({{$f.Type}})).(*{{$f.Type}})
+	{{- end}}
+	return res, true
+}
+{{end}}
+{{- end}}
+`
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"go/format"
+	"os"
+	"testing"
+
+	"github.com/flashbots/suapp-examples/framework"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden bindings")
+
+// goldenBindings are the bindings of the fixture artifact. They are a package of
+// the module, so the build of the tree also checks that the generated code compiles.
+const goldenBindings = "internal/fixture/bindings.go"
+
+func TestGenerate(t *testing.T) {
+	artifact, err := framework.NewDirArtifactLoader("testdata").LoadArtifact("Fixture.sol/Fixture.json")
+	require.NoError(t, err)
+
+	gen := newGenerator("fixture")
+	require.NoError(t, gen.addContract("Fixture", "Fixture.sol/Fixture.json", artifact))
+	code, err := gen.generate()
+	require.NoError(t, err)
+	formatted, err := format.Source(code)
+	require.NoError(t, err, "the generated code is not valid Go:\n%s", code)
+
+	if *update {
+		require.NoError(t, os.WriteFile(goldenBindings, formatted, 0o644))
+	}
+	golden, err := os.ReadFile(goldenBindings)
+	require.NoError(t, err)
+	require.Equal(t, string(golden), string(formatted), "run 'go test ./cmd/suappgen -update' to update the golden bindings")
+}
+
+func TestContractName(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{path: "ofa-private.sol/OFAPrivate.json", want: "OFAPrivate"},
+		{path: "out/ofa-private.sol/OFAPrivate.json", want: "OFAPrivate"},
+		{path: "combined.json:Bundle", want: "Bundle"},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, contractName(tc.path), tc.path)
+	}
+}
+
+func TestArgName(t *testing.T) {
+	cases := []struct {
+		name  string
+		i     int
+		field bool
+		want  string
+	}{
+		{name: "bid_id", want: "bidId"},
+		{name: "bid_id", field: true, want: "BidId"},
+		{name: "", i: 2, want: "arg2"},
+		{name: "", i: 2, field: true, want: "Arg2"},
+		{name: "type", want: "type_"},
+		{name: "type", field: true, want: "Type"},
+		{name: "ctx", want: "ctx_"},
+		{name: "opts", want: "opts_"},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.want, argName(tc.name, tc.i, tc.field), "%q field=%t", tc.name, tc.field)
+	}
+}
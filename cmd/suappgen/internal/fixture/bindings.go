@@ -0,0 +1,203 @@
+// Code generated by suappgen. DO NOT EDIT.
+
+package fixture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/flashbots/suapp-examples/framework"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = context.Background
+	_ = errors.As
+	_ = fmt.Errorf
+	_ = big.NewInt
+	_ = abi.ConvertType
+	_ = common.Address{}
+	_ = types.Receipt{}
+)
+
+// FixtureBid is an auto generated low-level Go binding around a user-defined struct.
+type FixtureBid struct {
+	Id     uint64
+	Bidder common.Address
+	Data   []byte
+}
+
+// FixtureArtifact is the artifact of the Fixture Suapp.
+const FixtureArtifact = "Fixture.sol/Fixture.json"
+
+// Fixture is a typed binding of the Fixture Suapp.
+type Fixture struct {
+	*framework.Contract
+}
+
+// NewFixture wraps a deployed Fixture contract.
+func NewFixture(contract *framework.Contract) *Fixture {
+	return &Fixture{Contract: contract}
+}
+
+// DeployFixture deploys the Fixture Suapp.
+func DeployFixture(ctx context.Context, chain *framework.Chain, initial *big.Int, owner common.Address, opts ...framework.DeployOption) (*Fixture, error) {
+	opts = append([]framework.DeployOption{framework.WithConstructorArgs(initial, owner)}, opts...)
+	contract, err := chain.DeployContractE(ctx, FixtureArtifact, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewFixture(contract), nil
+}
+
+// SubmitBid sends a confidential request to 'submitBid((uint64,address,bytes),uint64[])'.
+func (c *Fixture) SubmitBid(ctx context.Context, bid FixtureBid, blocks []uint64, confidentialInputs []byte, opts ...framework.RequestOption) (*types.Receipt, error) {
+	return c.SendConfidentialRequestE(ctx, "submitBid", []interface{}{bid, blocks}, confidentialInputs, opts...)
+}
+
+// BidCount calls the view function 'bidCount()'.
+func (c *Fixture) BidCount(ctx context.Context) (uint64, error) {
+	out, err := c.CallE(ctx, "bidCount", []interface{}{})
+	if err != nil {
+		return *new(uint64), err
+	}
+	return *abi.ConvertType(out[0], new(uint64)).(*uint64), nil
+}
+
+// Check calls the view function 'check()'.
+func (c *Fixture) Check(ctx context.Context) error {
+	out, err := c.CallE(ctx, "check", []interface{}{})
+	_ = out
+	return err
+}
+
+// FixtureGetBidOutput is the output of 'getBid(uint64)'.
+type FixtureGetBidOutput struct {
+	Bid   FixtureBid
+	Found bool
+}
+
+// GetBid calls the view function 'getBid(uint64)'.
+func (c *Fixture) GetBid(ctx context.Context, id uint64) (*FixtureGetBidOutput, error) {
+	out, err := c.CallE(ctx, "getBid", []interface{}{id})
+	if err != nil {
+		return nil, err
+	}
+	res := new(FixtureGetBidOutput)
+	res.Bid = *abi.ConvertType(out[0], new(FixtureBid)).(*FixtureBid)
+	res.Found = *abi.ConvertType(out[1], new(bool)).(*bool)
+	return res, nil
+}
+
+// Hashes calls the view function 'hashes(bytes32[2])'.
+func (c *Fixture) Hashes(ctx context.Context, ids [2][32]byte) (*big.Int, error) {
+	out, err := c.CallE(ctx, "hashes", []interface{}{ids})
+	if err != nil {
+		return *new(*big.Int), err
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// OwnerOf calls the view function 'ownerOf(uint8)'.
+func (c *Fixture) OwnerOf(ctx context.Context, type_ uint8) (common.Address, error) {
+	out, err := c.CallE(ctx, "ownerOf", []interface{}{type_})
+	if err != nil {
+		return *new(common.Address), err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// FixtureBidSubmitted is the 'BidSubmitted(uint64,string,(uint64,address,bytes))' event.
+type FixtureBidSubmitted struct {
+	Id    uint64
+	Label common.Hash
+	Bid   FixtureBid
+	Raw   *types.Log
+}
+
+// ParseBidSubmitted decodes a 'BidSubmitted(uint64,string,(uint64,address,bytes))' event.
+func (c *Fixture) ParseBidSubmitted(log *types.Log) (*FixtureBidSubmitted, error) {
+	event := new(FixtureBidSubmitted)
+	if err := c.UnpackLog(event, "BidSubmitted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// FilterBidSubmitted returns the 'BidSubmitted(uint64,string,(uint64,address,bytes))' events emitted by the contract in the receipt.
+func (c *Fixture) FilterBidSubmitted(receipt *types.Receipt) ([]*FixtureBidSubmitted, error) {
+	var events []*FixtureBidSubmitted
+	for _, log := range receipt.Logs {
+		if log.Address != c.Raw().Address() || len(log.Topics) == 0 || log.Topics[0] != c.Abi.Events["BidSubmitted"].ID {
+			continue
+		}
+		event, err := c.ParseBidSubmitted(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// FixtureBidTooLow is the 'BidTooLow(uint256,address)' custom error.
+type FixtureBidTooLow struct {
+	Min    *big.Int
+	Bidder common.Address
+}
+
+func (e *FixtureBidTooLow) Error() string {
+	return fmt.Sprintf("BidTooLow%+v", *e)
+}
+
+// AsFixtureBidTooLow returns the 'BidTooLow(uint256,address)' error carried by err, if any.
+func AsFixtureBidTooLow(err error) (*FixtureBidTooLow, bool) {
+	var revertErr *framework.RevertError
+	if !errors.As(err, &revertErr) {
+		return nil, false
+	}
+	if revertErr.Inner != nil {
+		revertErr = revertErr.Inner
+	}
+	if revertErr.Selector != [4]byte{0x90, 0xdf, 0x98, 0xd1} || len(revertErr.Args) != 2 {
+		return nil, false
+	}
+	res := new(FixtureBidTooLow)
+	res.Min = *abi.ConvertType(revertErr.Args[0], new(*big.Int)).(**big.Int)
+	res.Bidder = *abi.ConvertType(revertErr.Args[1], new(common.Address)).(*common.Address)
+	return res, true
+}
+
+// FixturePeekerReverted is the 'PeekerReverted(address,bytes)' custom error.
+type FixturePeekerReverted struct {
+	Arg0 common.Address
+	Arg1 []byte
+}
+
+func (e *FixturePeekerReverted) Error() string {
+	return fmt.Sprintf("PeekerReverted%+v", *e)
+}
+
+// AsFixturePeekerReverted returns the 'PeekerReverted(address,bytes)' error carried by err, if any.
+func AsFixturePeekerReverted(err error) (*FixturePeekerReverted, bool) {
+	var revertErr *framework.RevertError
+	if !errors.As(err, &revertErr) {
+		return nil, false
+	}
+	if revertErr.Inner != nil {
+		revertErr = revertErr.Inner
+	}
+	if revertErr.Selector != [4]byte{0x75, 0xff, 0xf4, 0x67} || len(revertErr.Args) != 2 {
+		return nil, false
+	}
+	res := new(FixturePeekerReverted)
+	res.Arg0 = *abi.ConvertType(revertErr.Args[0], new(common.Address)).(*common.Address)
+	res.Arg1 = *abi.ConvertType(revertErr.Args[1], new([]byte)).(*[]byte)
+	return res, true
+}
@@ -0,0 +1,62 @@
+package fixture
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/flashbots/suapp-examples/framework"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsCustomError(t *testing.T) {
+	artifact, err := framework.NewDirArtifactLoader("../../testdata").LoadArtifact(FixtureArtifact)
+	require.NoError(t, err)
+	decoder := framework.NewRevertDecoder(artifact.Abi)
+
+	bidder := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	bidTooLow := artifact.Abi.Errors["BidTooLow"]
+	args, err := bidTooLow.Inputs.Pack(big.NewInt(100), bidder)
+	require.NoError(t, err)
+	data := append(append([]byte{}, bidTooLow.ID[:4]...), args...)
+
+	cases := []struct {
+		name string
+		err  error
+		want *FixtureBidTooLow
+	}{
+		{
+			name: "revert",
+			err:  decoder.Decode(data),
+			want: &FixtureBidTooLow{Min: big.NewInt(100), Bidder: bidder},
+		},
+		{
+			name: "wrapped revert",
+			err:  fmt.Errorf("call failed: %w", decoder.Decode(data)),
+			want: &FixtureBidTooLow{Min: big.NewInt(100), Bidder: bidder},
+		},
+		{
+			name: "nested in a peeker revert",
+			err:  &framework.RevertError{Name: "PeekerReverted", Inner: decoder.Decode(data)},
+			want: &FixtureBidTooLow{Min: big.NewInt(100), Bidder: bidder},
+		},
+		{
+			name: "another error",
+			err:  decoder.Decode(append([]byte{0x01, 0x02, 0x03, 0x04}, args...)),
+		},
+		{
+			name: "not a revert",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := AsFixtureBidTooLow(tc.err)
+			require.Equal(t, tc.want != nil, ok)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
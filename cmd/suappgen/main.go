@@ -0,0 +1,80 @@
+// suappgen generates typed Go bindings for Suapps on top of framework.Contract.
+//
+// Usage:
+//
+//	suappgen [-pkg main] [-out bindings.go] [-artifacts out] <artifact>...
+//
+// Artifacts are referenced like in framework.ReadArtifact, e.g. 'ofa-private.sol/OFAPrivate.json'.
+// Generate all the bindings of a package in a single invocation so that the structs
+// shared by several contracts are only declared once.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+
+	"github.com/flashbots/suapp-examples/framework"
+)
+
+func main() {
+	var (
+		pkg          = flag.String("pkg", "main", "package name of the generated code")
+		out          = flag.String("out", "", "output file (default stdout)")
+		artifactsDir = flag.String("artifacts", "", "directory with the compiled artifacts (default: "+framework.ArtifactsDirEnv+" or the Foundry 'out' folder)")
+		typeName     = flag.String("type", "", "Go type of the binding, only valid with a single artifact (default: contract name)")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: suappgen [flags] <artifact>...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *typeName != "" && len(paths) != 1 {
+		log.Fatal("-type can only be used with a single artifact")
+	}
+
+	loader := framework.DefaultArtifactLoader
+	if *artifactsDir != "" {
+		loader = framework.NewDirArtifactLoader(*artifactsDir)
+	}
+
+	gen := newGenerator(*pkg)
+	for _, path := range paths {
+		artifact, err := loader.LoadArtifact(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		name := *typeName
+		if name == "" {
+			name = contractName(path)
+		}
+		if err := gen.addContract(name, path, artifact); err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+	}
+
+	code, err := gen.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	formatted, err := format.Source(code)
+	if err != nil {
+		log.Fatalf("failed to format the generated code: %v\n%s", err, code)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(formatted)
+		return
+	}
+	if err := os.WriteFile(*out, formatted, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
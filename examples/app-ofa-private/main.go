@@ -9,7 +9,6 @@ import (
 	"math/big"
 	"net/http"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/flashbots/suapp-examples/framework"
@@ -58,18 +57,16 @@ func main() {
 
 	targetAddr := testAddr1.Address()
 
-	ethTxn1, _ := fr.L1.SignTx(testAddr1, &types.LegacyTx{
-		To:       &targetAddr,
-		Value:    big.NewInt(1000),
-		Gas:      21000,
-		GasPrice: big.NewInt(670189871),
+	ethTxn1, _ := fr.L1.SignTx(testAddr1, &types.DynamicFeeTx{
+		To:    &targetAddr,
+		Value: big.NewInt(1000),
+		Gas:   21000,
 	})
 
-	ethTxnBackrun, _ := fr.L1.SignTx(testAddr2, &types.LegacyTx{
-		To:       &targetAddr,
-		Value:    big.NewInt(1000),
-		Gas:      21420,
-		GasPrice: big.NewInt(670189871),
+	ethTxnBackrun, _ := fr.L1.SignTx(testAddr2, &types.DynamicFeeTx{
+		To:    &targetAddr,
+		Value: big.NewInt(1000),
+		Gas:   21420,
 	})
 
 	// Step 2. Send the initial transaction
@@ -92,10 +89,7 @@ func main() {
 	// new dataRecord inputs
 	receipt := contract.SendConfidentialRequest("newOrder", []interface{}{target + 1}, bundleBytes)
 
-	hintEvent := &HintEvent{}
-	if err := hintEvent.Unpack(receipt.Logs[0]); err != nil {
-		panic(err)
-	}
+	hintEvent := framework.MustEvent[HintEvent](contract, receipt, "HintEvent")
 
 	fmt.Println("Hint event id", hintEvent.DataRecordId)
 
@@ -117,10 +111,7 @@ func main() {
 	// backrun inputs
 	receipt = contract.SendConfidentialRequest("newMatch", []interface{}{hintEvent.DataRecordId, target + 1}, backRunBundleBytes)
 
-	matchEvent := &HintEvent{}
-	if err := matchEvent.Unpack(receipt.Logs[0]); err != nil {
-		panic(err)
-	}
+	matchEvent := framework.MustEvent[HintEvent](contract, receipt, "HintEvent")
 
 	fmt.Println("Match event id", matchEvent.DataRecordId)
 
@@ -128,7 +119,7 @@ func main() {
 	fmt.Println("4. Emit batch")
 
 	receipt = contract.SendConfidentialRequest("emitMatchDataRecordAndHint", []interface{}{cfg.BuilderURL, matchEvent.DataRecordId}, backRunBundleBytes)
-	bundleHash, err := decodeBundleEmittedOutput(receipt)
+	bundleHash, err := decodeBundleEmittedOutput(contract, receipt)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -136,35 +127,21 @@ func main() {
 	fmt.Println("Bundle hash", bundleHash)
 }
 
-var (
-	hintEventABI       abi.Event
-	bundleEmittedEvent abi.Event
-)
-
-func init() {
-	artifact, _ := framework.ReadArtifact("ofa-private.sol/OFAPrivate.json")
-	hintEventABI = artifact.Abi.Events["HintEvent"]
-	bundleEmittedEvent = artifact.Abi.Events["BundleEmitted"]
+type HintEvent struct {
+	DataRecordId [16]byte `abi:"id"`
+	Hint         []byte   `abi:"hint"`
 }
 
-type HintEvent struct {
-	DataRecordId [16]byte
-	Hint         []byte
+type BundleEmitted struct {
+	BundleRawResponse string `abi:"bundleRawResponse"`
 }
 
-func (h *HintEvent) Unpack(log *types.Log) error {
-	res, err := hintEventABI.ParseLog(log)
+func decodeBundleEmittedOutput(contract *framework.Contract, receipt *types.Receipt) (string, error) {
+	bundleEmitted, err := framework.FindEvent[BundleEmitted](contract, receipt, "BundleEmitted")
 	if err != nil {
-		return err
+		return "", err
 	}
-	h.DataRecordId, _ = res["id"].([16]byte)
-	h.Hint, _ = res["hint"].([]byte)
-	return nil
-}
-
-func decodeBundleEmittedOutput(receipt *types.Receipt) (string, error) {
-	bundleEmitted, _ := bundleEmittedEvent.ParseLog(receipt.Logs[0])
-	response, _ := bundleEmitted["bundleRawResponse"].(string)
+	response := bundleEmitted.BundleRawResponse
 
 	log.Printf("mev_share response: %s", response)
 
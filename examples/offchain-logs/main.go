@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/flashbots/suapp-examples/framework"
 )
 
@@ -10,14 +11,32 @@ func main() {
 	fr := framework.New()
 	contract := fr.Suave.DeployContract("offchain-logs.sol/OffchainLogs.json")
 
+	// dry-run the confidential computation to check the callback
+	res := contract.SimulateConfidentialRequest("example", nil, nil)
+	if res.Method == nil || res.Method.Name != "emitCallbackWithLogs" {
+		log.Fatalf("unexpected callback 0x%x", res.Selector)
+	}
+
 	receipt := contract.SendConfidentialRequest("example", nil, nil)
 	if len(receipt.Logs) != 2 {
 		log.Fatal("two logs expected")
 	}
+	printEvents(contract, receipt)
 
 	// emit the CCR but DO NOT leak the logs
 	receipt = contract.SendConfidentialRequest("exampleNoLogs", nil, nil)
 	if len(receipt.Logs) != 1 {
 		log.Fatal("only one log expected")
 	}
+	printEvents(contract, receipt)
+}
+
+func printEvents(contract *framework.Contract, receipt *types.Receipt) {
+	events, err := contract.DecodeEvents(receipt, framework.WithOffchainEvents())
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, event := range events {
+		log.Printf("%s(num=%v) offchain=%v", event.Name, event.Args["num"], event.Offchain)
+	}
 }
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/flashbots/suapp-examples/framework"
 )
 
@@ -13,16 +12,14 @@ func main() {
 
 	// Deploy the contract and get the bid id
 	receipt := suapp.SendConfidentialRequest("registerContract", nil, privateLibrary.Code)
-	event, _ := contractRegisteredABI.Inputs.Unpack(receipt.Logs[0].Data)
-	privateContractBidId := event[0].([16]byte)
+	event := framework.MustEvent[ContractRegistered](suapp, receipt, "ContractRegistered")
+	privateContractBidId := event.DataId
 
 	// Use the private contract
 	suapp.SendConfidentialRequest("example", []interface{}{privateContractBidId}, nil)
 }
 
-var contractRegisteredABI abi.Event
-
-func init() {
-	artifact, _ := framework.ReadArtifact("lib-confidential-store.sol/PublicSuapp.json")
-	contractRegisteredABI = artifact.Abi.Events["ContractRegistered"]
+// ContractRegistered is the event emitted when the private contract is stored.
+type ContractRegistered struct {
+	DataId [16]byte `abi:"dataId"`
 }
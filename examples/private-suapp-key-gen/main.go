@@ -15,8 +15,14 @@ func main() {
 	receipt := contract.SendConfidentialRequest("example", nil, nil)
 
 	// validate the signature (TODO: return the address from the Suapp and validate the signature)
-	_, err := contract.Abi.Events["TxnSignature"].ParseLog(receipt.Logs[0])
+	_, err := framework.FindEvent[TxnSignature](contract, receipt, "TxnSignature")
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// TxnSignature is the event emitted with the signature of the transaction.
+type TxnSignature struct {
+	R [32]byte `abi:"r"`
+	S [32]byte `abi:"s"`
+}
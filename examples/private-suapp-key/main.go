@@ -18,11 +18,11 @@ func main() {
 	receipt := contract.SendConfidentialRequest("example", nil, nil)
 
 	// validate the signature
-	txnSignatureEvent, err := contract.Abi.Events["TxnSignature"].ParseLog(receipt.Logs[0])
+	txnSignatureEvent, err := framework.FindEvent[TxnSignature](contract, receipt, "TxnSignature")
 	if err != nil {
 		log.Fatal(err)
 	}
-	r, s := txnSignatureEvent["r"].([32]byte), txnSignatureEvent["s"].([32]byte)
+	r, s := txnSignatureEvent.R, txnSignatureEvent.S
 
 	if hex.EncodeToString(r[:]) != "eebcfac0def6db5649d0ae6b52ed3b8ba1f5c6c428588df125461113ba8c6749" {
 		log.Fatal("wrong r signature")
@@ -31,3 +31,9 @@ func main() {
 		log.Fatal("wrong s signature")
 	}
 }
+
+// TxnSignature is the event emitted with the signature of the transaction.
+type TxnSignature struct {
+	R [32]byte `abi:"r"`
+	S [32]byte `abi:"s"`
+}
@@ -25,14 +25,18 @@ func main() {
 	receipt := contract.
 		SendConfidentialRequest("example", []interface{}{endpoint, targetErc20Contract, balanceCheckAddr}, nil)
 
-	balanceEvent, err := contract.Abi.Events["Balance"].ParseLog(receipt.Logs[0])
+	balanceEvent, err := framework.FindEvent[Balance](contract, receipt, "Balance")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	balance := balanceEvent["balance"].(*big.Int)
-	if balance.Uint64() == 0 {
+	if balanceEvent.Balance.Uint64() == 0 {
 		// in Ethereum mainnet this balance is not zero
 		log.Fatal("balance is 0?")
 	}
 }
+
+// Balance is the event emitted with the balance of the account.
+type Balance struct {
+	Balance *big.Int `abi:"balance"`
+}
@@ -0,0 +1,194 @@
+package framework
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultMnemonic is the mnemonic of the test accounts when none is configured.
+// It is the well-known development mnemonic of Foundry and Hardhat.
+const DefaultMnemonic = "test test test test test test test test test test test junk"
+
+// DefaultDerivationPath is the base derivation path of the accounts of a
+// mnemonic, the account i is derived at '<path>/i'.
+const DefaultDerivationPath = "m/44'/60'/0'/0"
+
+// defaultFundedAccounts are the addresses of the default KETTLE_PRIVKEY and L1_PRIVKEY.
+var defaultFundedAccounts = map[common.Address]bool{
+	common.HexToAddress("0xBE69d72ca5f88aCba033a063dF5DBe43a4148De0"): true,
+	common.HexToAddress("0xB5fEAfbDD752ad52Afb7e1bD2E40432A485bBB7F"): true,
+}
+
+// devChainIDs are the chain IDs of the local devnets geth --dev and Anvil or
+// Hardhat. suave-geth --suave.dev shares its chain ID with the Rigil testnet.
+var devChainIDs = map[uint64]bool{
+	1337:  true,
+	31337: true,
+}
+
+// isDevChain reports whether the chain of client is a local devnet, i.e. its
+// chain ID is a devnet one or it is suave-geth --suave.dev.
+func isDevChain(ctx context.Context, client *rpc.Client, chainID *big.Int) (bool, error) {
+	if chainID.IsUint64() && devChainIDs[chainID.Uint64()] {
+		return true, nil
+	}
+	if chainID.Cmp(params.DeveloperSuaveChainConfig.ChainID) != 0 {
+		return false, nil
+	}
+	genesis, err := ethclient.NewClient(client).HeaderByNumber(ctx, common.Big0)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch the genesis block: %w", err)
+	}
+	return isDevGenesis(chainID, genesis), nil
+}
+
+// isDevGenesis reports whether the genesis is the one of suave-geth --suave.dev,
+// which leaves its timestamp at zero. The genesis of Rigil, which shares its chain
+// ID, is timestamped at its launch. Only the SUAVE chain ID is checked since other
+// live networks, like Ethereum mainnet, have a genesis timestamped at zero too.
+func isDevGenesis(chainID *big.Int, genesis *types.Header) bool {
+	return chainID.Cmp(params.DeveloperSuaveChainConfig.ChainID) == 0 &&
+		genesis.Number.Sign() == 0 && genesis.Time == 0
+}
+
+// DeriveAccount derives the account at index of the BIP-39 mnemonic, under the
+// base derivation path (DefaultDerivationPath if empty).
+func DeriveAccount(mnemonic, path string, index uint32) (*PrivKey, error) {
+	accts, err := deriveAccounts(mnemonic, path, index, 1)
+	if err != nil {
+		return nil, err
+	}
+	return accts[0], nil
+}
+
+func deriveAccounts(mnemonic, path string, start uint32, n int) ([]*PrivKey, error) {
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+	base, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path '%s': %w", path, err)
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(strings.Join(strings.Fields(mnemonic), " "), "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+
+	accts := make([]*PrivKey, n)
+	for i := range accts {
+		key, err := deriveKey(seed, append(base, start+uint32(i)))
+		if err != nil {
+			return nil, err
+		}
+		accts[i] = &PrivKey{Priv: key}
+	}
+	return accts, nil
+}
+
+// deriveKey derives the BIP-32 private key at path from the seed.
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	curveOrder := crypto.S256().Params().N
+
+	sum := hmacSHA512([]byte("Bitcoin seed"), seed)
+	key, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	if key.Sign() == 0 || key.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("invalid master key")
+	}
+
+	for _, index := range path {
+		var data []byte
+		if index >= 0x80000000 {
+			// hardened child
+			data = append([]byte{0}, math.PaddedBigBytes(key, 32)...)
+		} else {
+			parent, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&parent.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		sum := hmacSHA512(chainCode, data)
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(curveOrder) >= 0 {
+			return nil, fmt.Errorf("invalid child key at %s", path)
+		}
+		key = tweak.Add(tweak, key).Mod(tweak, curveOrder)
+		if key.Sign() == 0 {
+			return nil, fmt.Errorf("invalid child key at %s", path)
+		}
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// LoadKeystoreAccount loads the account addr from the encrypted keystore in dir and
+// unlocks it with the password in passwordFile. If addr is the zero address, it
+// loads the only account of the keystore.
+func LoadKeystoreAccount(dir, passwordFile string, addr common.Address) (*KeystoreSigner, error) {
+	var password string
+	if passwordFile != "" {
+		data, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore password: %w", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+
+	var account accounts.Account
+	if addr == (common.Address{}) {
+		accts := ks.Accounts()
+		if len(accts) != 1 {
+			return nil, fmt.Errorf("keystore %s has %d accounts, select one", dir, len(accts))
+		}
+		account = accts[0]
+	} else {
+		var err error
+		if account, err = ks.Find(accounts.Account{Address: addr}); err != nil {
+			return nil, fmt.Errorf("account %s not found in keystore %s: %w", addr.Hex(), dir, err)
+		}
+	}
+	return NewKeystoreSigner(ks, account, password)
+}
+
+// Accounts returns n deterministic test accounts derived from the configured
+// mnemonic, or DefaultMnemonic. The first account of the mnemonic is skipped
+// since it is the funded account when the mnemonic is configured.
+func (f *Framework) Accounts(n int) []*PrivKey {
+	mnemonic := f.config.Mnemonic
+	if mnemonic == "" {
+		mnemonic = DefaultMnemonic
+	}
+	accts, err := deriveAccounts(mnemonic, f.config.DerivationPath, 1, n)
+	if err != nil {
+		panic(err)
+	}
+	return accts
+}
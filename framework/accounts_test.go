@@ -0,0 +1,146 @@
+package framework
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveAccount(t *testing.T) {
+	cases := []struct {
+		name     string
+		mnemonic string
+		path     string
+		index    uint32
+		want     common.Address
+		wantErr  bool
+	}{
+		{name: "foundry account 0", mnemonic: DefaultMnemonic, index: 0, want: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")},
+		{name: "foundry account 1", mnemonic: DefaultMnemonic, index: 1, want: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")},
+		{name: "foundry account 2", mnemonic: DefaultMnemonic, index: 2, want: common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")},
+		{name: "extra whitespace", mnemonic: "  test test test test test test\ttest test test test test junk\n", index: 0, want: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")},
+		{name: "explicit path", mnemonic: DefaultMnemonic, path: "m/44'/60'/0'/0", index: 1, want: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")},
+		{name: "invalid checksum", mnemonic: "test test test test test test test test test test test test", wantErr: true},
+		{name: "unknown word", mnemonic: "test test test test test test test test test test test suave", wantErr: true},
+		{name: "invalid path", mnemonic: DefaultMnemonic, path: "m/44'/sixty'", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			acct, err := DeriveAccount(tc.mnemonic, tc.path, tc.index)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, acct.Address())
+		})
+	}
+}
+
+// TestDeriveKey checks the test vector 1 of BIP-32, with hardened and
+// non-hardened children.
+func TestDeriveKey(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{path: "m", want: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{path: "m/0'", want: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{path: "m/0'/1", want: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{path: "m/0'/1/2'", want: "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{path: "m/0'/1/2'/2", want: "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			var path accounts.DerivationPath
+			if tc.path != "m" {
+				path, err = accounts.ParseDerivationPath(tc.path)
+				require.NoError(t, err)
+			}
+			key, err := deriveKey(seed, path)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, hex.EncodeToString(crypto.FromECDSA(key)))
+		})
+	}
+}
+
+func TestIsDevGenesis(t *testing.T) {
+	suaveID := params.DeveloperSuaveChainConfig.ChainID
+	dev := core.DeveloperGenesisBlock(0, 30_000_000, common.HexToAddress("0xB5fEAfbDD752ad52Afb7e1bD2E40432A485bBB7F"))
+
+	cases := []struct {
+		name    string
+		chainID *big.Int
+		genesis *types.Header
+		want    bool
+	}{
+		{name: "suave dev", chainID: suaveID, genesis: dev.ToBlock().Header(), want: true},
+		{name: "rigil", chainID: suaveID, genesis: core.DefaultSuaveGenesisBlock().ToBlock().Header()},
+		{name: "not the genesis", chainID: suaveID, genesis: &types.Header{Number: big.NewInt(1)}},
+		{name: "mainnet", chainID: big.NewInt(1), genesis: core.DefaultGenesisBlock().ToBlock().Header()},
+		{name: "zero timestamp on another chain", chainID: big.NewInt(1), genesis: &types.Header{Number: big.NewInt(0)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, isDevGenesis(tc.chainID, tc.genesis))
+		})
+	}
+}
+
+// testGenesisAPI serves the genesis block of a chain.
+type testGenesisAPI struct {
+	genesis *types.Header
+}
+
+func (a *testGenesisAPI) GetBlockByNumber(number string, full bool) *types.Header {
+	return a.genesis
+}
+
+func TestIsDevChain(t *testing.T) {
+	suaveID := params.DeveloperSuaveChainConfig.ChainID
+	// a genesis timestamped at zero, like the ones of suave-geth --suave.dev and mainnet
+	zeroTime := &types.Header{Number: big.NewInt(0), Difficulty: common.Big0}
+	launched := &types.Header{Number: big.NewInt(0), Difficulty: common.Big0, Time: 1700000000}
+
+	cases := []struct {
+		name    string
+		chainID *big.Int
+		genesis *types.Header
+		want    bool
+	}{
+		{name: "geth dev", chainID: big.NewInt(1337), genesis: launched, want: true},
+		{name: "anvil", chainID: big.NewInt(31337), genesis: launched, want: true},
+		{name: "suave dev", chainID: suaveID, genesis: zeroTime, want: true},
+		{name: "rigil", chainID: suaveID, genesis: launched},
+		{name: "mainnet", chainID: big.NewInt(1), genesis: zeroTime},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := rpc.NewServer()
+			require.NoError(t, server.RegisterName("eth", &testGenesisAPI{genesis: tc.genesis}))
+			client := rpc.DialInProc(server)
+			defer client.Close()
+
+			dev, err := isDevChain(context.Background(), client, tc.chainID)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, dev)
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package framework
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Artifact struct {
+	Abi *abi.ABI
+
+	// Code is the code to deploy the contract. The placeholders of
+	// the linked libraries are zeroed, use Link to fill them.
+	Code []byte
+
+	// LinkReferences are the libraries referenced by Code
+	LinkReferences LinkReferences
+
+	// DeployedCode is the runtime code of the contract
+	DeployedCode []byte
+
+	// DeployedLinkReferences are the libraries referenced by DeployedCode
+	DeployedLinkReferences LinkReferences
+
+	// SourceMap and DeployedSourceMap are the solc source maps of Code and
+	// DeployedCode. They are empty if the artifact format does not include them.
+	SourceMap         string
+	DeployedSourceMap string
+
+	// MethodIdentifiers maps the method signatures to their hex encoded selector
+	MethodIdentifiers map[string]string
+}
+
+// LinkReferences maps a source file and a library name to the
+// positions of the library address in the bytecode.
+type LinkReferences map[string]map[string][]LinkReference
+
+// LinkReference is the position of a library address in the bytecode.
+type LinkReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// check checks that the reference is the position of an address in a code of size bytes.
+func (r LinkReference) check(size int) error {
+	if r.Length != common.AddressLength {
+		return fmt.Errorf("link reference at %d has length %d instead of %d", r.Start, r.Length, common.AddressLength)
+	}
+	if r.Start < 0 || r.Start+r.Length > size {
+		return fmt.Errorf("link reference at %d out of bounds of the %d bytes code", r.Start, size)
+	}
+	return nil
+}
+
+// ArtifactLoader loads the compiled artifacts of the Suapps.
+type ArtifactLoader interface {
+	// LoadArtifact loads the artifact at path, i.e. '<file>.sol/<Name>.json'.
+	// Files with several contracts (solc standard-JSON output and build-info
+	// files) select the contract with a ':<Name>' or ':<source>:<Name>' suffix.
+	LoadArtifact(path string) (*Artifact, error)
+}
+
+// DefaultArtifactLoader is the loader used by ReadArtifact and by the
+// frameworks without an explicit artifact source.
+var DefaultArtifactLoader ArtifactLoader = NewEnvArtifactLoader()
+
+// ReadArtifact reads an artifact with the DefaultArtifactLoader.
+func ReadArtifact(path string) (*Artifact, error) {
+	return DefaultArtifactLoader.LoadArtifact(path)
+}
+
+type fsArtifactLoader struct {
+	fsys fs.FS
+}
+
+// NewFSArtifactLoader returns a loader that reads the artifacts from fsys.
+// Use it with an embed.FS (and fs.Sub) to bundle the artifacts in a binary.
+func NewFSArtifactLoader(fsys fs.FS) ArtifactLoader {
+	return &fsArtifactLoader{fsys: fsys}
+}
+
+// NewDirArtifactLoader returns a loader that reads the artifacts from
+// a directory on disk, usually the Foundry 'out' folder.
+func NewDirArtifactLoader(dir string) ArtifactLoader {
+	return &fsArtifactLoader{fsys: os.DirFS(dir)}
+}
+
+func (l *fsArtifactLoader) LoadArtifact(path string) (*Artifact, error) {
+	file, contract := splitArtifactPath(path)
+
+	data, err := fs.ReadFile(l.fsys, filepath.ToSlash(file))
+	if err != nil {
+		return nil, &ArtifactError{Path: path, Err: err}
+	}
+	artifact, err := ParseArtifact(data, contract)
+	if err != nil {
+		return nil, &ArtifactError{Path: path, Err: err}
+	}
+	return artifact, nil
+}
+
+// ArtifactsDirEnv is the environment variable with the artifacts directory.
+const ArtifactsDirEnv = "ARTIFACTS_DIR"
+
+// NewEnvArtifactLoader returns a loader that reads the artifacts from the
+// directory in the ARTIFACTS_DIR environment variable. If it is not set, it
+// falls back to the 'out' folder of this repository or of the working directory.
+func NewEnvArtifactLoader() ArtifactLoader {
+	return &envArtifactLoader{}
+}
+
+type envArtifactLoader struct{}
+
+func (l *envArtifactLoader) LoadArtifact(path string) (*Artifact, error) {
+	return NewDirArtifactLoader(defaultArtifactsDir()).LoadArtifact(path)
+}
+
+func defaultArtifactsDir() string {
+	if dir := os.Getenv(ArtifactsDirEnv); dir != "" {
+		return dir
+	}
+
+	// the 'out' folder next to the framework sources, when running from this repository
+	if _, filename, _, ok := runtime.Caller(0); ok {
+		dir := filepath.Join(filepath.Dir(filename), "../out")
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	return "out"
+}
+
+// splitArtifactPath splits 'file.json:contract' into the file and the contract selector.
+func splitArtifactPath(path string) (string, string) {
+	idx := strings.Index(path, ".json:")
+	if idx == -1 {
+		return path, ""
+	}
+	return path[:idx+len(".json")], path[idx+len(".json:"):]
+}
+
+// ParseArtifact decodes an artifact. It detects the format among Foundry
+// artifacts, Hardhat artifacts, solc standard-JSON outputs and Foundry or
+// Hardhat build-info files. The latter two hold several contracts, one of them
+// is selected with 'contract', either '<Name>' or '<source>:<Name>'.
+func ParseArtifact(data []byte, contract string) (*Artifact, error) {
+	var probe struct {
+		Format    string          `json:"_format"`
+		Bytecode  json.RawMessage `json:"bytecode"`
+		Contracts json.RawMessage `json:"contracts"`
+		Output    *struct {
+			Contracts json.RawMessage `json:"contracts"`
+		} `json:"output"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	var (
+		artifact *Artifact
+		err      error
+	)
+	switch {
+	case probe.Output != nil && probe.Output.Contracts != nil:
+		// Foundry and Hardhat build-info files wrap the solc output
+		artifact, err = parseSolcOutput(probe.Output.Contracts, contract)
+	case probe.Contracts != nil:
+		artifact, err = parseSolcOutput(probe.Contracts, contract)
+	case strings.HasPrefix(probe.Format, "hh-sol-artifact"):
+		artifact, err = parseHardhatArtifact(data)
+	default:
+		artifact, err = parseFoundryArtifact(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if artifact.Abi == nil {
+		return nil, fmt.Errorf("artifact has no abi")
+	}
+	if len(artifact.Code) == 0 {
+		return nil, ErrNoBytecode
+	}
+	if len(artifact.MethodIdentifiers) == 0 {
+		artifact.MethodIdentifiers = map[string]string{}
+		for _, method := range artifact.Abi.Methods {
+			artifact.MethodIdentifiers[method.Sig] = hex.EncodeToString(method.ID)
+		}
+	}
+	return artifact, nil
+}
+
+// bytecodeObject is the bytecode as found in Foundry artifacts and solc outputs.
+type bytecodeObject struct {
+	Object         string         `json:"object"`
+	SourceMap      string         `json:"sourceMap"`
+	LinkReferences LinkReferences `json:"linkReferences"`
+}
+
+func newArtifact(contractAbi *abi.ABI, bytecode, deployed bytecodeObject, methodIdentifiers map[string]string) (*Artifact, error) {
+	code, err := decodeBytecode(bytecode.Object, bytecode.LinkReferences)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode: %w", err)
+	}
+	deployedCode, err := decodeBytecode(deployed.Object, deployed.LinkReferences)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployed bytecode: %w", err)
+	}
+
+	art := &Artifact{
+		Abi:                    contractAbi,
+		Code:                   code,
+		LinkReferences:         bytecode.LinkReferences,
+		DeployedCode:           deployedCode,
+		DeployedLinkReferences: deployed.LinkReferences,
+		SourceMap:              bytecode.SourceMap,
+		DeployedSourceMap:      deployed.SourceMap,
+		MethodIdentifiers:      methodIdentifiers,
+	}
+	return art, nil
+}
+
+// parseFoundryArtifact decodes the 'out/<file>.sol/<Name>.json' artifacts of Foundry.
+func parseFoundryArtifact(data []byte) (*Artifact, error) {
+	var artifact struct {
+		Abi               *abi.ABI          `json:"abi"`
+		Bytecode          bytecodeObject    `json:"bytecode"`
+		DeployedBytecode  bytecodeObject    `json:"deployedBytecode"`
+		MethodIdentifiers map[string]string `json:"methodIdentifiers"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, err
+	}
+	return newArtifact(artifact.Abi, artifact.Bytecode, artifact.DeployedBytecode, artifact.MethodIdentifiers)
+}
+
+// parseHardhatArtifact decodes the 'artifacts/<file>.sol/<Name>.json' artifacts of Hardhat.
+func parseHardhatArtifact(data []byte) (*Artifact, error) {
+	var artifact struct {
+		Abi                    *abi.ABI       `json:"abi"`
+		Bytecode               string         `json:"bytecode"`
+		DeployedBytecode       string         `json:"deployedBytecode"`
+		LinkReferences         LinkReferences `json:"linkReferences"`
+		DeployedLinkReferences LinkReferences `json:"deployedLinkReferences"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, err
+	}
+
+	bytecode := bytecodeObject{Object: artifact.Bytecode, LinkReferences: artifact.LinkReferences}
+	deployed := bytecodeObject{Object: artifact.DeployedBytecode, LinkReferences: artifact.DeployedLinkReferences}
+	return newArtifact(artifact.Abi, bytecode, deployed, nil)
+}
+
+// parseSolcOutput decodes a contract from the 'contracts' field of a solc standard-JSON output.
+func parseSolcOutput(data []byte, contract string) (*Artifact, error) {
+	var contracts map[string]map[string]struct {
+		Abi *abi.ABI `json:"abi"`
+		Evm struct {
+			Bytecode          bytecodeObject    `json:"bytecode"`
+			DeployedBytecode  bytecodeObject    `json:"deployedBytecode"`
+			MethodIdentifiers map[string]string `json:"methodIdentifiers"`
+		} `json:"evm"`
+	}
+	if err := json.Unmarshal(data, &contracts); err != nil {
+		return nil, err
+	}
+
+	source, name := "", contract
+	if idx := strings.LastIndex(contract, ":"); idx != -1 {
+		source, name = contract[:idx], contract[idx+1:]
+	}
+
+	var matches [][2]string
+	for file, fileContracts := range contracts {
+		if source != "" && file != source {
+			continue
+		}
+		for contractName := range fileContracts {
+			if name == "" || contractName == name {
+				matches = append(matches, [2]string{file, contractName})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("contract '%s' not found", contract)
+	}
+	if len(matches) != 1 {
+		names := make([]string, len(matches))
+		for i, match := range matches {
+			names[i] = match[0] + ":" + match[1]
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("contract '%s' is ambiguous, select one of: %s", contract, strings.Join(names, ", "))
+	}
+
+	found := contracts[matches[0][0]][matches[0][1]]
+	return newArtifact(found.Abi, found.Evm.Bytecode, found.Evm.DeployedBytecode, found.Evm.MethodIdentifiers)
+}
+
+// decodeBytecode decodes the hex bytecode replacing the library
+// placeholders with zeros.
+func decodeBytecode(object string, refs LinkReferences) ([]byte, error) {
+	object = strings.TrimPrefix(object, "0x")
+
+	hexCode := []byte(object)
+	for _, libs := range refs {
+		for _, positions := range libs {
+			for _, pos := range positions {
+				if err := pos.check(len(hexCode) / 2); err != nil {
+					return nil, err
+				}
+				start, end := pos.Start*2, (pos.Start+pos.Length)*2
+				copy(hexCode[start:end], strings.Repeat("0", end-start))
+			}
+		}
+	}
+	return hex.DecodeString(string(hexCode))
+}
+
+// Link returns the deploy code with the addresses of the referenced libraries.
+// The libraries are indexed either by name or by '<source>:<name>'.
+func (a *Artifact) Link(libraries map[string]common.Address) ([]byte, error) {
+	code := common.CopyBytes(a.Code)
+	for source, libs := range a.LinkReferences {
+		for name, positions := range libs {
+			addr, ok := libraries[source+":"+name]
+			if !ok {
+				addr, ok = libraries[name]
+			}
+			if !ok {
+				return nil, fmt.Errorf("%w: %s:%s", ErrMissingLibrary, source, name)
+			}
+			for _, pos := range positions {
+				if err := pos.check(len(code)); err != nil {
+					return nil, fmt.Errorf("library %s:%s: %w", source, name, err)
+				}
+				copy(code[pos.Start:pos.Start+pos.Length], addr.Bytes())
+			}
+		}
+	}
+	return code, nil
+}
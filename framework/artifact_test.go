@@ -0,0 +1,189 @@
+package framework
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactLink(t *testing.T) {
+	lib := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	code := append([]byte{0x60, 0x01}, make([]byte, 20)...)
+	code = append(code, 0x00)
+
+	linked := append([]byte{0x60, 0x01}, lib.Bytes()...)
+	linked = append(linked, 0x00)
+
+	cases := []struct {
+		name      string
+		refs      LinkReferences
+		libraries map[string]common.Address
+		want      []byte
+		wantErr   error
+	}{
+		{
+			name:      "by name",
+			refs:      LinkReferences{"src/Lib.sol": {"Lib": {{Start: 2, Length: 20}}}},
+			libraries: map[string]common.Address{"Lib": lib},
+			want:      linked,
+		},
+		{
+			name:      "by source and name",
+			refs:      LinkReferences{"src/Lib.sol": {"Lib": {{Start: 2, Length: 20}}}},
+			libraries: map[string]common.Address{"src/Lib.sol:Lib": lib},
+			want:      linked,
+		},
+		{
+			name: "no references",
+			want: code,
+		},
+		{
+			name:    "missing library",
+			refs:    LinkReferences{"src/Lib.sol": {"Lib": {{Start: 2, Length: 20}}}},
+			wantErr: ErrMissingLibrary,
+		},
+		{
+			name:      "out of bounds",
+			refs:      LinkReferences{"src/Lib.sol": {"Lib": {{Start: 10, Length: 20}}}},
+			libraries: map[string]common.Address{"Lib": lib},
+		},
+		{
+			name:      "negative start",
+			refs:      LinkReferences{"src/Lib.sol": {"Lib": {{Start: -1, Length: 20}}}},
+			libraries: map[string]common.Address{"Lib": lib},
+		},
+		{
+			name:      "not an address",
+			refs:      LinkReferences{"src/Lib.sol": {"Lib": {{Start: 2, Length: 4}}}},
+			libraries: map[string]common.Address{"Lib": lib},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			artifact := &Artifact{Code: code, LinkReferences: tc.refs}
+			got, err := artifact.Link(tc.libraries)
+			if tc.want == nil {
+				require.Error(t, err)
+				if tc.wantErr != nil {
+					require.ErrorIs(t, err, tc.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	// the code of the artifact is left unlinked
+	require.Equal(t, make([]byte, 20), code[2:22])
+}
+
+func TestDecodeBytecode(t *testing.T) {
+	placeholder := "__$0123456789abcdef0123456789abcdef01$__"
+	object := "0x6001" + placeholder + "00"
+
+	code, err := decodeBytecode(object, LinkReferences{"Lib.sol": {"Lib": {{Start: 2, Length: 20}}}})
+	require.NoError(t, err)
+	require.Equal(t, append(append([]byte{0x60, 0x01}, make([]byte, 20)...), 0x00), code)
+
+	_, err = decodeBytecode(object, LinkReferences{"Lib.sol": {"Lib": {{Start: 20, Length: 20}}}})
+	require.Error(t, err)
+
+	_, err = decodeBytecode(object, LinkReferences{"Lib.sol": {"Lib": {{Start: 2, Length: 19}}}})
+	require.Error(t, err)
+
+	_, err = decodeBytecode(object, nil)
+	require.Error(t, err, "unlinked placeholder is not hex")
+}
+
+const testArtifactAbi = `[{"type": "function", "name": "get", "inputs": [], "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view"}]`
+
+func TestParseArtifact(t *testing.T) {
+	foundry := `{
+		"abi": ` + testArtifactAbi + `,
+		"bytecode": {"object": "0x600100", "sourceMap": "1:2:3", "linkReferences": {}},
+		"deployedBytecode": {"object": "0x6002", "linkReferences": {}},
+		"methodIdentifiers": {"get()": "6d4ce63c"}
+	}`
+	hardhat := `{
+		"_format": "hh-sol-artifact-1",
+		"abi": ` + testArtifactAbi + `,
+		"bytecode": "0x600100",
+		"deployedBytecode": "0x6002",
+		"linkReferences": {},
+		"deployedLinkReferences": {}
+	}`
+	solcContracts := `{
+		"src/A.sol": {
+			"A": {"abi": ` + testArtifactAbi + `, "evm": {"bytecode": {"object": "600100"}, "deployedBytecode": {"object": "6002"}}},
+			"B": {"abi": ` + testArtifactAbi + `, "evm": {"bytecode": {"object": "600200"}, "deployedBytecode": {"object": "6003"}}}
+		},
+		"src/Other.sol": {
+			"A": {"abi": ` + testArtifactAbi + `, "evm": {"bytecode": {"object": "600300"}, "deployedBytecode": {"object": "6004"}}}
+		}
+	}`
+	solc := `{"contracts": ` + solcContracts + `}`
+	buildInfo := `{"id": "1", "output": {"contracts": ` + solcContracts + `}}`
+
+	cases := []struct {
+		name      string
+		data      string
+		contract  string
+		wantCode  []byte
+		wantError bool
+	}{
+		{name: "foundry", data: foundry, wantCode: []byte{0x60, 0x01, 0x00}},
+		{name: "hardhat", data: hardhat, wantCode: []byte{0x60, 0x01, 0x00}},
+		{name: "solc by name", data: solc, contract: "B", wantCode: []byte{0x60, 0x02, 0x00}},
+		{name: "solc by source and name", data: solc, contract: "src/Other.sol:A", wantCode: []byte{0x60, 0x03, 0x00}},
+		{name: "solc ambiguous name", data: solc, contract: "A", wantError: true},
+		{name: "solc unknown contract", data: solc, contract: "C", wantError: true},
+		{name: "build-info", data: buildInfo, contract: "src/A.sol:A", wantCode: []byte{0x60, 0x01, 0x00}},
+		{name: "no bytecode", data: `{"abi": ` + testArtifactAbi + `, "bytecode": {"object": "0x"}}`, wantError: true},
+		{name: "no abi", data: `{"bytecode": {"object": "0x6001"}}`, wantError: true},
+		{name: "invalid json", data: `{`, wantError: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			artifact, err := ParseArtifact([]byte(tc.data), tc.contract)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCode, artifact.Code)
+			require.Contains(t, artifact.Abi.Methods, "get")
+			require.Equal(t, "6d4ce63c", artifact.MethodIdentifiers["get()"])
+		})
+	}
+
+	artifact, err := ParseArtifact([]byte(foundry), "")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x60, 0x02}, artifact.DeployedCode)
+	require.Equal(t, "1:2:3", artifact.SourceMap)
+}
+
+func TestFSArtifactLoader(t *testing.T) {
+	fsys := fstest.MapFS{
+		"A.sol/A.json": {Data: []byte(`{"abi": ` + testArtifactAbi + `, "bytecode": {"object": "0x6001"}}`)},
+		"solc.json":    {Data: []byte(`{"contracts": {"A.sol": {"A": {"abi": ` + testArtifactAbi + `, "evm": {"bytecode": {"object": "6005"}}}}}}`)},
+	}
+	loader := NewFSArtifactLoader(fsys)
+
+	artifact, err := loader.LoadArtifact("A.sol/A.json")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x60, 0x01}, artifact.Code)
+
+	artifact, err = loader.LoadArtifact("solc.json:A.sol:A")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x60, 0x05}, artifact.Code)
+
+	_, err = loader.LoadArtifact("Missing.sol/Missing.json")
+	var artifactErr *ArtifactError
+	require.ErrorAs(t, err, &artifactErr)
+	require.Equal(t, "Missing.sol/Missing.json", artifactErr.Path)
+}
@@ -0,0 +1,101 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// CallbackError is returned when the confidential computation of a request
+// succeeds but its onchain callback reverts.
+type CallbackError struct {
+	Receipt *types.Receipt
+
+	// Selector is the 4-byte selector of the callback.
+	Selector [4]byte
+
+	// Method is the name of the callback method, empty if it is not in the ABI.
+	Method string
+
+	// GasUsed is the gas used by the failed callback.
+	GasUsed uint64
+
+	// Err is the reason the callback reverted, usually a *RevertError. It is
+	// nil if the reason could not be recovered.
+	Err error
+}
+
+func (e *CallbackError) Error() string {
+	name := e.Method
+	if name == "" {
+		name = fmt.Sprintf("0x%x", e.Selector)
+	}
+	reason := "reason unavailable"
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	return fmt.Sprintf("callback %s in transaction %s failed (gas used %d): %s", name, e.Receipt.TxHash.Hex(), e.GasUsed, reason)
+}
+
+func (e *CallbackError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrTransactionFailed}
+	}
+	return []error{ErrTransactionFailed, e.Err}
+}
+
+// callbackError builds the error for a confidential request whose callback
+// failed. It replays the callback with an eth_call on the state of the parent of
+// the block of the receipt to recover the revert reason. The transactions included
+// before the callback in the same block are not applied.
+func (c *Contract) callbackError(ctx context.Context, receipt *types.Receipt) error {
+	cbErr := &CallbackError{
+		Receipt: receipt,
+		GasUsed: receipt.GasUsed,
+	}
+
+	clt, err := c.chain.client(ctx)
+	if err != nil {
+		cbErr.Err = err
+		return cbErr
+	}
+	txn, _, err := clt.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		cbErr.Err = fmt.Errorf("failed to fetch callback transaction: %w", err)
+		return cbErr
+	}
+
+	calldata := txn.Data()
+	if len(calldata) >= 4 {
+		copy(cbErr.Selector[:], calldata[:4])
+		if method, err := c.Abi.MethodById(calldata[:4]); err == nil {
+			cbErr.Method = method.Name
+		}
+	}
+
+	signer, err := c.chain.signer(ctx)
+	if err != nil {
+		cbErr.Err = err
+		return cbErr
+	}
+	sender, err := types.Sender(signer, txn)
+	if err != nil {
+		cbErr.Err = fmt.Errorf("failed to recover callback sender: %w", err)
+		return cbErr
+	}
+
+	_, err = clt.CallContract(ctx, ethereum.CallMsg{
+		From:  sender,
+		To:    txn.To(),
+		Gas:   txn.Gas(),
+		Value: txn.Value(),
+		Data:  calldata,
+	}, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+	if err != nil {
+		cbErr.Err = c.decodeRevert(err)
+	}
+	return cbErr
+}
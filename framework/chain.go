@@ -0,0 +1,67 @@
+package framework
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ChainID returns the chain ID of the node, read when the chain connects.
+// Like RPC, it panics if the chain cannot be connected.
+func (c *Chain) ChainID() *big.Int {
+	c.mustConnect()
+	return new(big.Int).Set(c.chainID)
+}
+
+// Signer returns the go-ethereum signer of the transactions of the chain: the
+// SUAVE signer on the SUAVE chain, which accepts the confidential requests, and
+// the Cancun signer on the L1, which accepts the blob transactions.
+func (c *Chain) Signer() types.Signer {
+	c.mustConnect()
+	return c.txSigner()
+}
+
+func (c *Chain) txSigner() types.Signer {
+	if c.suave {
+		return types.NewSuaveSigner(c.chainID)
+	}
+	return types.NewCancunSigner(c.chainID)
+}
+
+// IsDevChain reports whether the chain is a local devnet: suave-geth --suave.dev,
+// geth --dev, Anvil or Hardhat. The default accounts are only used on these.
+func (c *Chain) IsDevChain() bool {
+	c.mustConnect()
+	return c.dev
+}
+
+// TransactOpts returns the options of the go-ethereum contract bindings to send
+// the transactions of acct on the chain, signed for its chain ID.
+func (c *Chain) TransactOpts(ctx context.Context, acct Signer) (*bind.TransactOpts, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	chainID := c.chainID
+	return &bind.TransactOpts{
+		From: acct.Address(),
+		Signer: func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != acct.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return acct.SignTx(ctx, tx, chainID)
+		},
+		Context: ctx,
+	}, nil
+}
+
+func (c *Chain) mustConnect() {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+
+	if err := c.Connect(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,162 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// ErrConfig is returned when the framework configuration cannot be loaded.
+	ErrConfig = errors.New("invalid framework config")
+
+	// ErrKettleAddress is returned when the kettle address cannot be resolved.
+	ErrKettleAddress = errors.New("failed to get kettle address")
+
+	// ErrTransactionFailed is returned when a transaction is included with a failed status.
+	ErrTransactionFailed = errors.New("transaction failed")
+
+	// ErrFundAccount is returned when a funded account does not hold the expected balance.
+	ErrFundAccount = errors.New("failed to fund account")
+
+	// ErrNoBytecode is returned when an artifact has no bytecode, i.e. it is an
+	// abstract contract or an interface.
+	ErrNoBytecode = errors.New("artifact has no bytecode, is the contract abstract or an interface?")
+
+	// ErrNotPayable is returned when value is sent to a constructor that is not payable.
+	ErrNotPayable = errors.New("constructor is not payable")
+
+	// ErrMissingLibrary is returned when the bytecode references a library without address.
+	ErrMissingLibrary = errors.New("missing library address")
+
+	// ErrEventNotFound is returned when a receipt does not include the expected event.
+	ErrEventNotFound = errors.New("event not found")
+
+	// ErrReceiptNotFound is returned when a transaction receipt does not show up in time.
+	ErrReceiptNotFound = errors.New("receipt not found")
+
+	// ErrNotSent is returned when waiting for a request signed with WithNoSend.
+	ErrNotSent = errors.New("request was signed but not sent")
+
+	// ErrDefaultAccount is returned when the well-known default keys are used
+	// against a chain that is not a local devnet.
+	ErrDefaultAccount = errors.New("default account used on a non-dev chain")
+
+	// ErrInvalidResult is returned when the SUAVE transaction of a confidential
+	// request is not signed by its kettle or does not wrap the signed request.
+	ErrInvalidResult = errors.New("invalid confidential compute result")
+
+	// ErrUnsupportedSigner is returned when a RemoteSigner is asked to sign
+	// something that the clef API cannot sign, like a blob transaction or a raw hash.
+	ErrUnsupportedSigner = errors.New("unsupported by remote signer")
+)
+
+// ArtifactError is returned when a compiled artifact cannot be read or decoded.
+type ArtifactError struct {
+	Path string
+	Err  error
+}
+
+func (e *ArtifactError) Error() string {
+	return fmt.Sprintf("artifact %s: %v", e.Path, e.Err)
+}
+
+func (e *ArtifactError) Unwrap() error {
+	return e.Err
+}
+
+// DialError is returned when an RPC endpoint cannot be reached.
+type DialError struct {
+	URL string
+	Err error
+}
+
+func (e *DialError) Error() string {
+	return fmt.Sprintf("failed to dial %s: %v", e.URL, e.Err)
+}
+
+func (e *DialError) Unwrap() error {
+	return e.Err
+}
+
+// DeployError is returned when a contract cannot be deployed.
+type DeployError struct {
+	Path string
+	Err  error
+}
+
+func (e *DeployError) Error() string {
+	return fmt.Sprintf("failed to deploy %s: %v", e.Path, e.Err)
+}
+
+func (e *DeployError) Unwrap() error {
+	return e.Err
+}
+
+// CallError is returned when a contract call or a confidential request fails.
+type CallError struct {
+	Method string
+	Err    error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Method, e.Err)
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// TxError is returned when a transaction is included in a block but its execution failed.
+type TxError struct {
+	Hash    common.Hash
+	Receipt *types.Receipt
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("transaction %s failed", e.Hash.Hex())
+}
+
+func (e *TxError) Unwrap() error {
+	return ErrTransactionFailed
+}
+
+// ReceiptTimeoutError is returned when the receipt of a transaction is not found
+// within Blocks blocks, or before the context is done (Err).
+type ReceiptTimeoutError struct {
+	Hash   common.Hash
+	Blocks uint64
+	Err    error
+}
+
+func (e *ReceiptTimeoutError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("receipt for %s not found: %v", e.Hash.Hex(), e.Err)
+	}
+	return fmt.Sprintf("receipt for %s not found within %d blocks", e.Hash.Hex(), e.Blocks)
+}
+
+func (e *ReceiptTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ReceiptTimeoutError) Is(target error) bool {
+	return target == ErrReceiptNotFound
+}
+
+// ResultError is returned when the SUAVE transaction Hash does not verify
+// against the confidential request it is the result of.
+type ResultError struct {
+	Hash   common.Hash
+	Reason string
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("result %s of confidential request: %s", e.Hash.Hex(), e.Reason)
+}
+
+func (e *ResultError) Unwrap() error {
+	return ErrInvalidResult
+}
@@ -0,0 +1,231 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Event is a log decoded with the ABI of a contract.
+type Event struct {
+	// Name is the name of the event in the ABI
+	Name string
+
+	// Args are the event arguments indexed by name. Indexed arguments of
+	// a dynamic type are only available as the hash of their value.
+	Args map[string]interface{}
+
+	Log *types.Log
+
+	// Offchain is set for the events emitted during the confidential
+	// computation of a request (see WithOffchainEvents), including the
+	// ones re-emitted on-chain by the callback.
+	Offchain bool
+}
+
+// Unmarshal copies the event arguments into out, a pointer to a struct. The
+// fields are matched with their `abi:"<name>"` tag or the camel-cased argument
+// name. Arguments without a matching field are skipped.
+func (e *Event) Unmarshal(out interface{}) error {
+	dst := reflect.ValueOf(out)
+	if dst.Kind() != reflect.Pointer || dst.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("event %s: expected a pointer to a struct, got %T", e.Name, out)
+	}
+	dst = dst.Elem()
+
+	fields := map[string]int{}
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if tag := field.Tag.Get("abi"); tag != "" {
+			fields[tag] = i
+		} else if _, ok := fields[field.Name]; !ok {
+			fields[field.Name] = i
+		}
+	}
+
+	for name, value := range e.Args {
+		idx, ok := fields[name]
+		if !ok {
+			idx, ok = fields[abi.ToCamelCase(name)]
+		}
+		if !ok {
+			continue
+		}
+
+		field := dst.Field(idx)
+		src := reflect.ValueOf(value)
+		switch {
+		case src.Type().AssignableTo(field.Type()):
+			field.Set(src)
+		case src.Type().ConvertibleTo(field.Type()):
+			field.Set(src.Convert(field.Type()))
+		default:
+			// i.e. tuples into user defined structs
+			converted, err := convertType(value, field.Type())
+			if err != nil {
+				return fmt.Errorf("event %s: cannot assign %s to field of type %s: %w", e.Name, src.Type(), field.Type(), err)
+			}
+			field.Set(converted)
+		}
+	}
+	return nil
+}
+
+// convertType converts value to typ with abi.ConvertType, which panics if
+// the types do not match.
+func convertType(value interface{}, typ reflect.Type) (converted reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return reflect.ValueOf(abi.ConvertType(value, reflect.New(typ).Interface())).Elem(), nil
+}
+
+// decodeLog decodes a log emitted by the event.
+func decodeLog(event abi.Event, log *types.Log) (*Event, error) {
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 || topics[0] != event.ID {
+			return nil, fmt.Errorf("log is not a '%s' event", event.Name)
+		}
+		topics = topics[1:]
+	}
+
+	args := map[string]interface{}{}
+	if len(log.Data) > 0 {
+		if err := event.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.Name, err)
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, topics); err != nil {
+		return nil, fmt.Errorf("event %s: %w", event.Name, err)
+	}
+
+	return &Event{Name: event.Name, Args: args, Log: log}, nil
+}
+
+// UnpackLog unpacks the event 'name' emitted in log into out, a pointer to
+// a struct with one field per event argument (see Event.Unmarshal).
+func (c *Contract) UnpackLog(out interface{}, name string, log *types.Log) error {
+	event, ok := c.Abi.Events[name]
+	if !ok {
+		return fmt.Errorf("event '%s' not found in abi", name)
+	}
+	decoded, err := decodeLog(event, log)
+	if err != nil {
+		return err
+	}
+	return decoded.Unmarshal(out)
+}
+
+// DecodeEvents decodes all the logs emitted by the contract in the receipt, in order.
+// Logs emitted by other addresses or not present in the ABI are skipped. The events
+// emitted off-chain are only decoded with WithOffchainEvents.
+func (c *Contract) DecodeEvents(receipt *types.Receipt, opts ...EventOption) ([]*Event, error) {
+	ctx, cancel := c.chain.defaultContext()
+	defer cancel()
+
+	return c.DecodeEventsE(ctx, receipt, opts...)
+}
+
+// DecodeEventsE is like DecodeEvents but takes a context.
+func (c *Contract) DecodeEventsE(ctx context.Context, receipt *types.Receipt, opts ...EventOption) ([]*Event, error) {
+	events, err := c.decodeLogs(receipt.Logs, false)
+	if err != nil {
+		return nil, err
+	}
+	if !newEventConfig(opts).offchain {
+		return events, nil
+	}
+
+	logs, err := c.chain.OffchainLogs(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	reemitted, logs := matchOffchainLogs(receipt.Logs, logs)
+	for _, event := range events {
+		event.Offchain = reemitted[event.Log]
+	}
+	offchain, err := c.decodeLogs(logs, true)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, offchain...), nil
+}
+
+func (c *Contract) decodeLogs(logs []*types.Log, offchain bool) ([]*Event, error) {
+	var events []*Event
+	for _, log := range logs {
+		if log.Address != c.addr || len(log.Topics) == 0 {
+			continue
+		}
+		event, err := c.Abi.EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+		decoded, err := decodeLog(*event, log)
+		if err != nil {
+			return nil, err
+		}
+		decoded.Offchain = offchain
+		events = append(events, decoded)
+	}
+	return events, nil
+}
+
+// FindEvents decodes all the 'name' events emitted by the contract in the receipt into T.
+// Like DecodeEvents, it only includes the events emitted off-chain with WithOffchainEvents.
+func FindEvents[T any](c *Contract, receipt *types.Receipt, name string, opts ...EventOption) ([]*T, error) {
+	events, err := c.DecodeEvents(receipt, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*T
+	for _, event := range events {
+		if event.Name != name {
+			continue
+		}
+		out := new(T)
+		if err := event.Unmarshal(out); err != nil {
+			return nil, err
+		}
+		res = append(res, out)
+	}
+	return res, nil
+}
+
+// FindEvent decodes the first 'name' event emitted by the contract in the receipt into T.
+func FindEvent[T any](c *Contract, receipt *types.Receipt, name string, opts ...EventOption) (*T, error) {
+	events, err := FindEvents[T](c, receipt, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, name)
+	}
+	return events[0], nil
+}
+
+// MustEvent is like FindEvent but panics if the event is not found.
+func MustEvent[T any](c *Contract, receipt *types.Receipt, name string, opts ...EventOption) *T {
+	event, err := FindEvent[T](c, receipt, name, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return event
+}
@@ -0,0 +1,124 @@
+package framework
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testEventsAbi = `[
+	{"type": "event", "name": "Transfer", "anonymous": false, "inputs": [
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "amount", "type": "uint256", "indexed": false},
+		{"name": "memo", "type": "string", "indexed": false}
+	]},
+	{"type": "event", "name": "Order", "anonymous": false, "inputs": [
+		{"name": "order", "type": "tuple", "indexed": false, "components": [
+			{"name": "id", "type": "uint64"},
+			{"name": "owner", "type": "address"}
+		]}
+	]}
+]`
+
+type testTransfer struct {
+	From   common.Address
+	Amount *big.Int
+	Note   string `abi:"memo"`
+}
+
+type testOrder struct {
+	Order struct {
+		Id    uint64
+		Owner common.Address
+	}
+}
+
+func testEventLog(t *testing.T, contractAbi abi.ABI, name string, topics []common.Hash, args ...interface{}) *types.Log {
+	t.Helper()
+
+	event := contractAbi.Events[name]
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	require.NoError(t, err)
+	return &types.Log{Topics: append([]common.Hash{event.ID}, topics...), Data: data}
+}
+
+func TestEventUnmarshal(t *testing.T) {
+	contractAbi, err := abi.JSON(strings.NewReader(testEventsAbi))
+	require.NoError(t, err)
+
+	from := common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	transferLog := testEventLog(t, contractAbi, "Transfer", []common.Hash{common.BytesToHash(from.Bytes())}, big.NewInt(42), "hello")
+
+	event, err := decodeLog(contractAbi.Events["Transfer"], transferLog)
+	require.NoError(t, err)
+	require.Equal(t, "Transfer", event.Name)
+
+	var transfer testTransfer
+	require.NoError(t, event.Unmarshal(&transfer))
+	require.Equal(t, testTransfer{From: from, Amount: big.NewInt(42), Note: "hello"}, transfer)
+
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000f2")
+	order := struct {
+		Id    uint64         `json:"id"`
+		Owner common.Address `json:"owner"`
+	}{Id: 7, Owner: owner}
+	event, err = decodeLog(contractAbi.Events["Order"], testEventLog(t, contractAbi, "Order", nil, order))
+	require.NoError(t, err)
+
+	var decodedOrder testOrder
+	require.NoError(t, event.Unmarshal(&decodedOrder))
+	require.Equal(t, uint64(7), decodedOrder.Order.Id)
+	require.Equal(t, owner, decodedOrder.Order.Owner)
+
+	_, err = decodeLog(contractAbi.Events["Order"], transferLog)
+	require.Error(t, err)
+}
+
+func TestEventUnmarshalErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		out  interface{}
+	}{
+		{
+			name: "not a pointer",
+			args: map[string]interface{}{"amount": big.NewInt(1)},
+			out:  testTransfer{},
+		},
+		{
+			name: "not a struct",
+			args: map[string]interface{}{"amount": big.NewInt(1)},
+			out:  new(int),
+		},
+		{
+			name: "mismatched field type",
+			args: map[string]interface{}{"amount": big.NewInt(1)},
+			out: &struct {
+				Amount string
+			}{},
+		},
+		{
+			name: "mismatched tuple",
+			args: map[string]interface{}{"order": struct {
+				Id uint64 `json:"id"`
+			}{Id: 1}},
+			out: &struct {
+				Order struct{ Id common.Address }
+			}{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := &Event{Name: "Test", Args: tc.args}
+			require.NotPanics(t, func() {
+				require.Error(t, event.Unmarshal(tc.out))
+			})
+		})
+	}
+}
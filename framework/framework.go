@@ -5,15 +5,13 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding"
-	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
-	"path/filepath"
-	"runtime"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -22,52 +20,10 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/ethereum/go-ethereum/suave/artifacts"
 	"github.com/ethereum/go-ethereum/suave/sdk"
-	envconfig "github.com/sethvargo/go-envconfig"
+	"github.com/sethvargo/go-envconfig"
 )
 
-type Artifact struct {
-	Abi *abi.ABI
-
-	// Code is the code to deploy the contract
-	Code []byte
-}
-
-func ReadArtifact(path string) (*Artifact, error) {
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		return nil, fmt.Errorf("unable to get the current filename")
-	}
-	dirname := filepath.Dir(filename)
-
-	data, err := os.ReadFile(filepath.Join(dirname, "../out", path))
-	if err != nil {
-		return nil, err
-	}
-
-	var artifact struct {
-		Abi      *abi.ABI `json:"abi"`
-		Bytecode struct {
-			Object string `json:"object"`
-		} `json:"bytecode"`
-	}
-	if err := json.Unmarshal(data, &artifact); err != nil {
-		return nil, err
-	}
-
-	code, err := hex.DecodeString(artifact.Bytecode.Object[2:])
-	if err != nil {
-		return nil, err
-	}
-
-	art := &Artifact{
-		Abi:  artifact.Abi,
-		Code: code,
-	}
-	return art, nil
-}
-
 var _ encoding.TextUnmarshaler = &PrivKey{}
 
 type PrivKey struct {
@@ -92,101 +48,178 @@ func (p *PrivKey) UnmarshalText(text []byte) error {
 }
 
 func NewPrivKeyFromHex(hex string) *PrivKey {
+	p, err := NewPrivKeyFromHexE(hex)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewPrivKeyFromHexE is like NewPrivKeyFromHex but returns an error instead of panicking.
+func NewPrivKeyFromHexE(hex string) (*PrivKey, error) {
 	p := new(PrivKey)
 	if err := p.UnmarshalText([]byte(hex)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func GeneratePrivKey() *PrivKey {
+	p, err := GeneratePrivKeyE()
+	if err != nil {
 		panic(err)
 	}
 	return p
 }
 
-func GeneratePrivKey() *PrivKey {
+// GeneratePrivKeyE is like GeneratePrivKey but returns an error instead of panicking.
+func GeneratePrivKeyE() (*PrivKey, error) {
 	key, err := crypto.GenerateKey()
 	if err != nil {
-		panic(fmt.Sprintf("failed to generate private key: %v", err))
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
 	}
-	return &PrivKey{Priv: key}
+	return &PrivKey{Priv: key}, nil
 }
 
 type Contract struct {
 	contract *sdk.Contract
 
-	clt        *sdk.Client
-	kettleAddr common.Address
+	chain   *Chain
+	account Signer
+
+	// kettle of the confidential requests, nil to select it with the kettle policy
+	kettle *common.Address
 
 	addr common.Address
 	Abi  *abi.ABI
 }
 
 func (c *Contract) Call(methodName string, args []interface{}) []interface{} {
-	input, err := c.Abi.Pack(methodName, args...)
+	ctx, cancel := c.chain.defaultContext()
+	defer cancel()
+
+	results, err := c.CallE(ctx, methodName, args)
 	if err != nil {
 		panic(err)
 	}
+	return results
+}
+
+// CallE is like Call but returns an error instead of panicking.
+func (c *Contract) CallE(ctx context.Context, methodName string, args []interface{}) ([]interface{}, error) {
+	input, err := c.Abi.Pack(methodName, args...)
+	if err != nil {
+		return nil, &CallError{Method: methodName, Err: err}
+	}
 
 	callMsg := ethereum.CallMsg{
+		From: c.account.Address(),
 		To:   &c.addr,
 		Data: input,
 	}
-	output, err := c.clt.RPC().CallContract(context.Background(), callMsg, nil)
+	clt, err := c.chain.client(ctx)
 	if err != nil {
-		panic(err)
+		return nil, &CallError{Method: methodName, Err: err}
+	}
+	output, err := clt.CallContract(ctx, callMsg, nil)
+	if err != nil {
+		return nil, &CallError{Method: methodName, Err: c.decodeRevert(err)}
 	}
 
 	results, err := c.Abi.Methods[methodName].Outputs.Unpack(output)
 	if err != nil {
-		panic(err)
+		return nil, &CallError{Method: methodName, Err: err}
 	}
-	return results
+	return results, nil
 }
 
+// Raw returns the contract of the go-ethereum SUAVE SDK. It can only send
+// transactions if the account of the contract is a *PrivKey.
 func (c *Contract) Raw() *sdk.Contract {
 	return c.contract
 }
 
 var executionRevertedPrefix = "execution reverted: 0x"
 
-// SendConfidentialRequest sends the confidential request to the kettle
-func (c *Contract) SendConfidentialRequest(method string, args []interface{}, confidentialBytes []byte) *types.Receipt {
-	txnResult, err := c.contract.SendTransaction(method, args, confidentialBytes)
-	if err != nil {
-		// decode the PeekerReverted error
-		errMsg := err.Error()
-		if strings.HasPrefix(errMsg, executionRevertedPrefix) {
-			errMsg = errMsg[len(executionRevertedPrefix):]
-			errMsgBytes, _ := hex.DecodeString(errMsg)
-
-			unpacked, _ := artifacts.SuaveAbi.Errors["PeekerReverted"].Inputs.Unpack(errMsgBytes[4:])
-
-			addr, _ := unpacked[0].(common.Address)
-			eventErr, _ := unpacked[1].([]byte)
-			panic(fmt.Sprintf("peeker 0x%x reverted: %s", addr, eventErr))
-		}
-		panic(err)
+// decodeRevert returns err as a *RevertError if it carries revert data
+// known to the contract ABI or the SUAVE precompiles.
+func (c *Contract) decodeRevert(err error) error {
+	if revertErr := NewRevertDecoder(c.Abi).DecodeError(err); revertErr != nil {
+		return revertErr
 	}
+	return err
+}
 
-	log.Printf("transaction hash: %s", txnResult.Hash().Hex())
+// SendConfidentialRequest sends the confidential request to the kettle
+func (c *Contract) SendConfidentialRequest(method string, args []interface{}, confidentialBytes []byte, opts ...RequestOption) *types.Receipt {
+	ctx, cancel := c.chain.defaultContext()
+	defer cancel()
 
-	receipt, err := txnResult.Wait()
+	receipt, err := c.SendConfidentialRequestE(ctx, method, args, confidentialBytes, opts...)
 	if err != nil {
 		panic(err)
 	}
-	if receipt.Status == 0 {
-		panic(fmt.Errorf("status not correct"))
-	}
 	return receipt
 }
 
+// SendConfidentialRequestE is like SendConfidentialRequest but returns an error instead of panicking.
+func (c *Contract) SendConfidentialRequestE(ctx context.Context, method string, args []interface{}, confidentialBytes []byte, opts ...RequestOption) (*types.Receipt, error) {
+	pending, err := c.SendConfidentialRequestAsync(ctx, method, args, confidentialBytes, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return pending.Wait(ctx)
+}
+
 type Framework struct {
-	config        *Config
-	KettleAddress common.Address
+	config *Config
+
+	// KettleAddresses are the addresses of all the kettles, KettleAddress is the
+	// first one. With WithLazyDial, they are only set once the SUAVE chain is
+	// connected, see Chain.Connect.
+	KettleAddress   common.Address
+	KettleAddresses []common.Address
+
+	Artifacts ArtifactLoader
 
 	Suave *Chain
 	L1    *Chain
+
+	// connections opened by the framework
+	mu      sync.Mutex
+	clients []*rpc.Client
 }
 
 type Config struct {
 	KettleRPC string `env:"KETTLE_RPC, default=http://localhost:8545"`
 
+	// Endpoints of several kettles of the SUAVE chain, comma separated in the
+	// environment. If set, they replace KettleRPC and the first one is also used
+	// for the requests that are not confidential.
+	KettleRPCs []string `env:"KETTLE_RPCS"`
+
+	// Addresses of the kettles to use among the ones reported by the kettle
+	// endpoints, comma separated in the environment. All of them if empty.
+	KettleAddresses []string `env:"KETTLE_ADDRESSES"`
+
+	// Policy that selects the kettle of the confidential requests, FailoverPolicy
+	// if nil.
+	KettlePolicy KettlePolicy
+
+	// Expected chain IDs of the SUAVE and L1 chains, checked when connecting.
+	// Zero accepts any chain.
+	SuaveChainID uint64 `env:"SUAVE_CHAIN_ID"`
+	L1ChainID    uint64 `env:"L1_CHAIN_ID"`
+
+	// Block explorers of the SUAVE and L1 chains, used to link the transactions in the logs.
+	SuaveExplorerURL string `env:"SUAVE_EXPLORER_URL"`
+	L1ExplorerURL    string `env:"L1_EXPLORER_URL"`
+
+	// Profile is the name of the profile the configuration was loaded from and
+	// ProfilesFile the file it is read from, see LoadConfig.
+	Profile      string
+	ProfilesFile string
+
 	// This account is funded in your local SUAVE devnet
 	// address: 0xBE69d72ca5f88aCba033a063dF5DBe43a4148De0
 	FundedAccount *PrivKey `env:"KETTLE_PRIVKEY, default=91ab9a7e53c220e6210460b65a7a3bb2ca181412a8a7b43ff336b3df1737ce12"`
@@ -197,8 +230,53 @@ type Config struct {
 	// address: 0xB5fEAfbDD752ad52Afb7e1bD2E40432A485bBB7F
 	FundedAccountL1 *PrivKey `env:"L1_PRIVKEY, default=6c45335a22461ccdb978b78ab61b238bad2fae4544fb55c14eb096c875ccfc52"`
 
+	// Encrypted keystores with the accounts of the SUAVE and L1 chains, and the
+	// files with their passwords. The account is selected with the *_KEYSTORE_ACCOUNT
+	// variables if the keystore has more than one.
+	Keystore               string `env:"KETTLE_KEYSTORE"`
+	KeystorePasswordFile   string `env:"KETTLE_KEYSTORE_PASSWORD_FILE"`
+	KeystoreAccount        string `env:"KETTLE_KEYSTORE_ACCOUNT"`
+	L1Keystore             string `env:"L1_KEYSTORE"`
+	L1KeystorePasswordFile string `env:"L1_KEYSTORE_PASSWORD_FILE"`
+	L1KeystoreAccount      string `env:"L1_KEYSTORE_ACCOUNT"`
+
+	// BIP-39 mnemonic of the accounts. If set, its first account is the funded
+	// account of both chains and Framework.Accounts derives the next ones.
+	Mnemonic       string `env:"MNEMONIC"`
+	DerivationPath string `env:"DERIVATION_PATH, default=m/44'/60'/0'/0"`
+
+	// Accounts of the SUAVE and L1 chains. They take precedence over
+	// the keystores, the mnemonic and the private keys.
+	Signer   Signer
+	L1Signer Signer
+
+	// Whether to verify that the SUAVE transactions of the confidential requests are
+	// signed by the kettle and wrap the signed requests, see Chain.VerifyResult.
+	VerifyResults bool `env:"VERIFY_RESULTS"`
+
+	// Maximum number of blocks to wait for a transaction receipt. Zero disables the limit.
+	ReceiptTimeoutBlocks uint64 `env:"RECEIPT_TIMEOUT_BLOCKS, default=10"`
+
+	// Timeout of the helpers that do not take a context. Zero disables the timeout.
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT, default=2m"`
+
+	// Directory with the compiled artifacts. If empty, the DefaultArtifactLoader is used.
+	ArtifactsDir string `env:"ARTIFACTS_DIR"`
+
+	// Loader of the compiled artifacts. It takes precedence over ArtifactsDir.
+	ArtifactLoader ArtifactLoader
+
 	// Whether to enable L1 or not
 	L1Enabled bool
+
+	// Clients of the SUAVE and L1 chains to use instead of dialing KettleRPC,
+	// KettleRPCs and L1RPC. The framework does not close them.
+	SuaveClient *rpc.Client `env:",noinit"`
+	L1Client    *rpc.Client `env:",noinit"`
+
+	// Whether to connect to the chains on their first use instead of in
+	// NewFromConfig, see Chain.Connect.
+	LazyDial bool
 }
 
 type ConfigOption func(c *Config)
@@ -209,134 +287,660 @@ func WithL1() ConfigOption {
 	}
 }
 
+// WithSuaveClient uses client for the SUAVE chain and its kettles instead of dialing them.
+func WithSuaveClient(client *rpc.Client) ConfigOption {
+	return func(c *Config) {
+		c.SuaveClient = client
+	}
+}
+
+// WithL1Client uses client for the L1 chain instead of dialing it, and enables it.
+func WithL1Client(client *rpc.Client) ConfigOption {
+	return func(c *Config) {
+		c.L1Client = client
+		c.L1Enabled = true
+	}
+}
+
+// WithLazyDial connects to the chains on their first use instead of in New.
+func WithLazyDial() ConfigOption {
+	return func(c *Config) {
+		c.LazyDial = true
+	}
+}
+
+// WithProfile loads the configuration from the named profile instead of the one
+// in SUAPP_PROFILE. The environment variables still override its fields.
+func WithProfile(name string) ConfigOption {
+	return func(c *Config) {
+		c.Profile = name
+	}
+}
+
+// WithProfilesFile sets the file the profile is read from, see LoadProfile.
+func WithProfilesFile(path string) ConfigOption {
+	return func(c *Config) {
+		c.ProfilesFile = path
+	}
+}
+
+// WithArtifactLoader sets the source of the compiled artifacts.
+func WithArtifactLoader(loader ArtifactLoader) ConfigOption {
+	return func(c *Config) {
+		c.ArtifactLoader = loader
+	}
+}
+
+// WithResultVerification verifies the SUAVE transaction of every confidential
+// request before returning its receipt.
+func WithResultVerification() ConfigOption {
+	return func(c *Config) {
+		c.VerifyResults = true
+	}
+}
+
+// WithKettlePolicy sets how the kettles of the confidential requests are selected.
+func WithKettlePolicy(policy KettlePolicy) ConfigOption {
+	return func(c *Config) {
+		c.KettlePolicy = policy
+	}
+}
+
+// WithSigner sets the account of the SUAVE chain, i.e. a KeystoreSigner or a RemoteSigner.
+func WithSigner(signer Signer) ConfigOption {
+	return func(c *Config) {
+		c.Signer = signer
+	}
+}
+
+// WithL1Signer sets the account of the L1 chain.
+func WithL1Signer(signer Signer) ConfigOption {
+	return func(c *Config) {
+		c.L1Signer = signer
+	}
+}
+
 func New(opts ...ConfigOption) *Framework {
-	var config Config
-	if err := envconfig.Process(context.Background(), &config); err != nil {
+	fr, err := NewE(context.Background(), opts...)
+	if err != nil {
 		log.Fatal(err)
 	}
-	for _, opt := range opts {
-		opt(&config)
-	}
+	return fr
+}
 
-	kettleRPC, err := rpc.Dial(config.KettleRPC)
+// NewE is like New but returns an error instead of exiting the process.
+func NewE(ctx context.Context, opts ...ConfigOption) (*Framework, error) {
+	config, err := LoadConfig(ctx, opts...)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return NewFromConfig(ctx, config)
+}
 
-	var accounts []common.Address
-	if err := kettleRPC.Call(&accounts, "eth_kettleAddress"); err != nil {
-		panic(fmt.Sprintf("failed to get kettle address: %v", err))
+// DefaultConfig returns the configuration with its default values, without
+// reading the environment nor a profile.
+func DefaultConfig() *Config {
+	var config Config
+	// the default values always parse
+	_ = envconfig.ProcessWith(context.Background(), &envconfig.Config{Target: &config, Lookuper: envconfig.MapLookuper(nil)})
+	return &config
+}
+
+// NewFromConfig creates the framework from config, without reading the environment
+// nor a profile. Start from DefaultConfig or LoadConfig to get the default values.
+// Unless config.LazyDial is set, it connects to the chains before returning.
+func NewFromConfig(ctx context.Context, config *Config) (*Framework, error) {
+	artifacts := config.ArtifactLoader
+	if artifacts == nil {
+		artifacts = DefaultArtifactLoader
+		if config.ArtifactsDir != "" {
+			artifacts = NewDirArtifactLoader(config.ArtifactsDir)
+		}
 	}
 
-	suaveClt := sdk.NewClient(kettleRPC, config.FundedAccount.Priv, accounts[0])
-	suaveClt.WithEIP712()
+	policy := config.KettlePolicy
+	if policy == nil {
+		policy = FailoverPolicy()
+	}
 
-	fr := &Framework{
-		config:        &config,
-		KettleAddress: accounts[0],
-		Suave:         &Chain{rpc: kettleRPC, clt: suaveClt, kettleAddr: accounts[0]},
+	suaveSigner, err := config.account(config.Signer, config.Keystore, config.KeystorePasswordFile, config.KeystoreAccount, config.FundedAccount)
+	if err != nil {
+		return nil, err
 	}
 
+	fr := &Framework{
+		config:    config,
+		Artifacts: artifacts,
+		Suave: &Chain{
+			account:       suaveSigner,
+			policy:        policy,
+			artifacts:     artifacts,
+			nonces:        newNonceManager(),
+			suave:         true,
+			eip712:        true,
+			verifyResults: config.VerifyResults,
+			explorer:      config.SuaveExplorerURL,
+			receiptBlocks: config.ReceiptTimeoutBlocks,
+			timeout:       config.RequestTimeout,
+		},
+	}
+	fr.Suave.connect = fr.connectSuave
+
 	if config.L1Enabled {
-		l1RPC, err := rpc.Dial(config.L1RPC)
+		l1Signer, err := config.account(config.L1Signer, config.L1Keystore, config.L1KeystorePasswordFile, config.L1KeystoreAccount, config.FundedAccountL1)
 		if err != nil {
-			panic(err)
+			return nil, err
+		}
+		fr.L1 = &Chain{
+			account:       l1Signer,
+			explorer:      config.L1ExplorerURL,
+			artifacts:     artifacts,
+			nonces:        newNonceManager(),
+			receiptBlocks: config.ReceiptTimeoutBlocks,
+			timeout:       config.RequestTimeout,
+		}
+		fr.L1.connect = fr.connectL1
+	}
+
+	if !config.LazyDial {
+		for _, chain := range []*Chain{fr.Suave, fr.L1} {
+			if chain == nil {
+				continue
+			}
+			if err := chain.Connect(ctx); err != nil {
+				fr.Close()
+				return nil, err
+			}
 		}
-		l1Clt := sdk.NewClient(l1RPC, config.FundedAccountL1.Priv, common.Address{})
-		fr.L1 = &Chain{rpc: l1RPC, clt: l1Clt}
 	}
+	return fr, nil
+}
 
-	return fr
+// dial connects to url, the connection is closed by Close.
+func (f *Framework) dial(ctx context.Context, url string) (*rpc.Client, error) {
+	if url == "" {
+		return nil, fmt.Errorf("%w: missing endpoint", ErrConfig)
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, &DialError{URL: url, Err: err}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clients = append(f.clients, client)
+	return client, nil
+}
+
+// connectSuave connects the SUAVE chain to its kettles and checks its account.
+func (f *Framework) connectSuave(ctx context.Context) error {
+	config := f.config
+
+	var (
+		kettles []*Kettle
+		err     error
+	)
+	if config.SuaveClient != nil {
+		kettles, err = resolveKettles(ctx, "", config.SuaveClient)
+	} else {
+		urls := config.KettleRPCs
+		if len(urls) == 0 {
+			urls = []string{config.KettleRPC}
+		}
+		for _, url := range urls {
+			client, err := f.dial(ctx, url)
+			if err != nil {
+				return err
+			}
+			resolved, err := resolveKettles(ctx, url, client)
+			if err != nil {
+				return err
+			}
+			kettles = append(kettles, resolved...)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if kettles, err = selectKettleAddresses(kettles, config.KettleAddresses); err != nil {
+		return err
+	}
+
+	client := kettles[0].rpc
+	chainID, err := fetchChainID(ctx, client, config.SuaveChainID)
+	if err != nil {
+		return err
+	}
+	dev, err := isDevChain(ctx, client, chainID)
+	if err != nil {
+		return err
+	}
+	if err := checkDevAccount(chainID, dev, f.Suave.account); err != nil {
+		return err
+	}
+
+	f.Suave.rpc = client
+	f.Suave.chainID = chainID
+	f.Suave.dev = dev
+	f.Suave.kettles = kettles
+	f.Suave.kettleAddr = kettles[0].Address
+
+	f.KettleAddress = kettles[0].Address
+	f.KettleAddresses = make([]common.Address, len(kettles))
+	for i, kettle := range kettles {
+		f.KettleAddresses[i] = kettle.Address
+	}
+	return nil
+}
+
+// connectL1 connects the L1 chain and checks its account.
+func (f *Framework) connectL1(ctx context.Context) error {
+	client := f.config.L1Client
+	if client == nil {
+		var err error
+		if client, err = f.dial(ctx, f.config.L1RPC); err != nil {
+			return err
+		}
+	}
+	chainID, err := fetchChainID(ctx, client, f.config.L1ChainID)
+	if err != nil {
+		return err
+	}
+	dev, err := isDevChain(ctx, client, chainID)
+	if err != nil {
+		return err
+	}
+	if err := checkDevAccount(chainID, dev, f.L1.account); err != nil {
+		return err
+	}
+	f.L1.rpc = client
+	f.L1.chainID = chainID
+	f.L1.dev = dev
+	return nil
+}
+
+// Close closes the connections opened by the framework. The clients set with
+// WithSuaveClient and WithL1Client are left open.
+func (f *Framework) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, client := range f.clients {
+		client.Close()
+	}
+	f.clients = nil
+}
+
+// account returns the funded account of a chain: the signer, the keystore
+// account, the first account of the mnemonic or the private key, in that order.
+func (c *Config) account(signer Signer, keystoreDir, passwordFile, keystoreAccount string, key *PrivKey) (Signer, error) {
+	switch {
+	case signer != nil:
+		return signer, nil
+	case keystoreDir != "":
+		var addr common.Address
+		if keystoreAccount != "" {
+			if !common.IsHexAddress(keystoreAccount) {
+				return nil, fmt.Errorf("%w: invalid keystore account '%s'", ErrConfig, keystoreAccount)
+			}
+			addr = common.HexToAddress(keystoreAccount)
+		}
+		acct, err := LoadKeystoreAccount(keystoreDir, passwordFile, addr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+		return acct, nil
+	case c.Mnemonic != "":
+		acct, err := DeriveAccount(c.Mnemonic, c.DerivationPath, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+		return acct, nil
+	case key == nil:
+		return nil, fmt.Errorf("%w: no account configured", ErrConfig)
+	}
+	return key, nil
+}
+
+// fetchChainID returns the chain ID of client and checks that it is the
+// expected one, if any.
+func fetchChainID(ctx context.Context, client *rpc.Client, want uint64) (*big.Int, error) {
+	chainID, err := ethclient.NewClient(client).ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if want != 0 && (!chainID.IsUint64() || chainID.Uint64() != want) {
+		return nil, fmt.Errorf("%w: expected chain %d, node is on chain %s", ErrConfig, want, chainID)
+	}
+	return chainID, nil
+}
+
+// checkDevAccount refuses the well-known default accounts on chains other than the local devnets.
+func checkDevAccount(chainID *big.Int, dev bool, acct Signer) error {
+	if !defaultFundedAccounts[acct.Address()] || dev {
+		return nil
+	}
+	return fmt.Errorf("%w: %s on chain %s, configure an account", ErrDefaultAccount, acct.Address().Hex(), chainID)
 }
 
 type Chain struct {
+	// connect sets rpc and the kettles of the chain, nil once it is connected
+	connMu  sync.Mutex
+	connect func(ctx context.Context) error
+
 	rpc        *rpc.Client
-	clt        *sdk.Client
+	account    Signer
 	kettleAddr common.Address
+	artifacts  ArtifactLoader
+	nonces     *nonceManager
+
+	// chain ID of the node and whether it is a local devnet, set when the
+	// chain is connected
+	chainID *big.Int
+	dev     bool
+
+	// whether the chain is a SUAVE chain, whose transactions are signed
+	// with the SUAVE signer
+	suave bool
+
+	// kettles of the confidential requests and the policy that selects them
+	kettles []*Kettle
+	policy  KettlePolicy
+
+	// whether confidential requests are signed with EIP-712
+	eip712 bool
+
+	// whether the results of the confidential requests are verified
+	verifyResults bool
+
+	// base URL of the block explorer, empty if none
+	explorer string
+
+	receiptBlocks uint64
+	timeout       time.Duration
+}
+
+type deployConfig struct {
+	txConfig
+	args      []interface{}
+	libraries map[string]common.Address
+}
+
+// DeployOption configures DeployContract. The TxOptions are also DeployOptions.
+type DeployOption interface {
+	applyDeploy(c *deployConfig)
+}
+
+type deployOption func(c *deployConfig)
+
+func (o deployOption) applyDeploy(c *deployConfig) { o(c) }
+
+// WithConstructorArgs sets the arguments of the contract constructor.
+func WithConstructorArgs(args ...interface{}) DeployOption {
+	return deployOption(func(c *deployConfig) {
+		c.args = args
+	})
+}
+
+// WithLibrary links the library 'name' to the given address. The name is either
+// the library name or its fully qualified '<source>:<name>' form.
+func WithLibrary(name string, addr common.Address) DeployOption {
+	return deployOption(func(c *deployConfig) {
+		if c.libraries == nil {
+			c.libraries = map[string]common.Address{}
+		}
+		c.libraries[name] = addr
+	})
 }
 
-func (c *Chain) DeployContract(path string) *Contract {
-	artifact, err := ReadArtifact(path)
+func (c *Chain) DeployContract(path string, opts ...DeployOption) *Contract {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+
+	contract, err := c.DeployContractE(ctx, path, opts...)
 	if err != nil {
 		panic(err)
 	}
+	return contract
+}
+
+// DeployContractE is like DeployContract but returns an error instead of panicking.
+func (c *Chain) DeployContractE(ctx context.Context, path string, opts ...DeployOption) (*Contract, error) {
+	artifact, err := c.artifacts.LoadArtifact(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg deployConfig
+	for _, opt := range opts {
+		opt.applyDeploy(&cfg)
+	}
+
+	code, err := artifact.Link(cfg.libraries)
+	if err != nil {
+		if !errors.Is(err, ErrMissingLibrary) {
+			err = &ArtifactError{Path: path, Err: err}
+		}
+		return nil, &DeployError{Path: path, Err: err}
+	}
+	input, err := artifact.Abi.Pack("", cfg.args...)
+	if err != nil {
+		return nil, &DeployError{Path: path, Err: fmt.Errorf("failed to pack constructor arguments: %w", err)}
+	}
+	code = append(code, input...)
+
+	if cfg.value != nil && cfg.value.Sign() != 0 && !artifact.Abi.Constructor.IsPayable() {
+		return nil, &DeployError{Path: path, Err: ErrNotPayable}
+	}
 
 	// deploy contract
-	txnResult, err := sdk.DeployContract(artifact.Code, c.clt)
+	txn := &types.LegacyTx{
+		Data:     code,
+		Value:    cfg.value,
+		Gas:      cfg.gas,
+		GasPrice: cfg.gasPrice,
+	}
+	hash, err := c.sendTransaction(ctx, c.account, txn, cfg.nonce)
 	if err != nil {
-		panic(err)
+		return nil, &DeployError{Path: path, Err: err}
 	}
 
-	receipt, err := txnResult.Wait()
+	receipt, err := c.WaitForReceipt(ctx, hash)
 	if err != nil {
-		panic(err)
+		return nil, &DeployError{Path: path, Err: err}
 	}
-	if receipt.Status == 0 {
-		panic(fmt.Errorf("transaction failed"))
+	if receipt.Status == types.ReceiptStatusFailed {
+		return nil, &DeployError{Path: path, Err: &TxError{Hash: receipt.TxHash, Receipt: receipt}}
 	}
 
 	log.Printf("deployed contract at %s", receipt.ContractAddress.Hex())
 
-	contract := sdk.GetContract(receipt.ContractAddress, artifact.Abi, c.clt)
-	return &Contract{addr: receipt.ContractAddress, clt: c.clt, kettleAddr: c.kettleAddr, Abi: artifact.Abi, contract: contract}
+	contract := sdk.GetContract(receipt.ContractAddress, artifact.Abi, c.sdkClient(c.account))
+	return &Contract{addr: receipt.ContractAddress, chain: c, account: c.account, Abi: artifact.Abi, contract: contract}, nil
+}
+
+// As returns a handle to the same contract that sends the transactions and
+// confidential requests from acct. The handle shares the chain, kettle and ABI
+// of c, creating one does not perform any request.
+func (c *Contract) As(acct Signer) *Contract {
+	cc := *c
+	cc.account = acct
+	cc.contract = sdk.GetContract(c.addr, c.Abi, c.chain.sdkClient(acct))
+	return &cc
 }
 
-func (c *Contract) Ref(acct *PrivKey) *Contract {
-	clt := sdk.NewClient(c.clt.RPC().Client(), acct.Priv, c.kettleAddr)
+// OnKettle returns a handle to the same contract that sends its confidential
+// requests to the kettle addr instead of selecting it with the kettle policy.
+// The WithKettle option still takes precedence for a single request.
+func (c *Contract) OnKettle(addr common.Address) *Contract {
+	cc := *c
+	cc.kettle = &addr
+	return &cc
+}
 
-	cc := &Contract{
-		addr:     c.addr,
-		Abi:      c.Abi,
-		contract: sdk.GetContract(c.addr, c.Abi, clt),
+// Ref returns a handle to the contract for acct.
+//
+// Deprecated: use As.
+func (c *Contract) Ref(acct Signer) *Contract {
+	return c.As(acct)
+}
+
+// sdkClient returns a client of the go-ethereum SUAVE SDK for acct. The SDK
+// needs an in-memory key to sign, with other signers it can only read.
+func (c *Chain) sdkClient(acct Signer) *sdk.Client {
+	var key *ecdsa.PrivateKey
+	if priv, ok := acct.(*PrivKey); ok {
+		key = priv.Priv
+	}
+	clt := sdk.NewClient(c.rpc, key, c.kettleAddr)
+	if c.eip712 {
+		clt.WithEIP712()
 	}
-	return cc
+	return clt
 }
 
-func (c *Chain) SignTx(priv *PrivKey, tx *types.LegacyTx) (*types.Transaction, error) {
-	cltAcct1 := sdk.NewClient(c.rpc, priv.Priv, common.Address{})
-	signedTxn, err := cltAcct1.SignTxn(tx)
+// SignTx signs tx with acct without sending it, i.e. to include it in a bundle. The
+// missing fields of tx are filled from the node first, see SignTxE.
+func (c *Chain) SignTx(acct Signer, tx types.TxData, opts ...TxOption) (*types.Transaction, error) {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+
+	return c.SignTxE(ctx, acct, tx, opts...)
+}
+
+// SignTxE is like SignTx but takes a context. tx is a types.LegacyTx, AccessListTx,
+// DynamicFeeTx or BlobTx, and it is not modified. Before signing, a nil chain ID is
+// replaced with the one of the chain, the missing fees with the ones suggested by
+// the node and a zero gas limit with an estimate. The EIP-1559 fee cap is twice the
+// base fee plus the tip, the suggested tip is capped to a fee cap set in tx, and the
+// blob fee cap is twice the blob base fee.
+//
+// A nonce set in tx, or with WithNonce for a zero nonce, is kept as is. Otherwise
+// the nonce is allocated like the ones of the transactions sent by the framework,
+// so signing several transactions of an account, i.e. the ones of a bundle, gives
+// them consecutive nonces. The framework does not send them: if one of them is
+// never included, call ReleaseNonce or ResetNonce so that the next send of the
+// account does not leave a gap. Of the TxOptions only WithNonce applies.
+func (c *Chain) SignTxE(ctx context.Context, acct Signer, tx types.TxData, opts ...TxOption) (*types.Transaction, error) {
+	var cfg txConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.nonce == nil {
+		if nonce := types.NewTx(tx).Nonce(); nonce != 0 {
+			cfg.nonce = &nonce
+		}
+	}
+	if cfg.nonce != nil {
+		return c.signTx(ctx, acct, tx, cfg.nonce)
+	}
+
+	clt, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addr := acct.Address()
+	nonce, err := c.nonces.next(ctx, addr, clt.PendingNonceAt)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := c.signTx(ctx, acct, tx, &nonce)
+	if err != nil {
+		c.nonces.release(addr, nonce)
+		return nil, err
+	}
+	return signed, nil
+}
+
+func (c *Chain) signTx(ctx context.Context, acct Signer, tx types.TxData, nonce *uint64) (*types.Transaction, error) {
+	signer, err := c.signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filled, err := c.fillTx(ctx, acct.Address(), tx, nonce)
 	if err != nil {
 		return nil, err
 	}
-	return signedTxn, nil
+	return acct.SignTx(ctx, types.NewTx(filled), signer.ChainID())
 }
 
-var errFundAccount = fmt.Errorf("failed to fund account")
+// Connect connects the chain if the framework was created with WithLazyDial and
+// the chain was not used yet. Since the chains connect on their first use, it is
+// only needed to handle the connection errors, RPC panics on them.
+func (c *Chain) Connect(ctx context.Context) error {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
 
+	if c.connect == nil {
+		return nil
+	}
+	if err := c.connect(ctx); err != nil {
+		return err
+	}
+	c.connect = nil
+	return nil
+}
+
+// RPC returns a client of the chain endpoint. It connects the chain if needed and
+// panics if it cannot, the E functions of the framework return the error instead.
 func (c *Chain) RPC() *ethclient.Client {
+	c.mustConnect()
 	return ethclient.NewClient(c.rpc)
 }
 
+// client connects the chain if needed and returns a client of its endpoint.
+func (c *Chain) client(ctx context.Context) (*ethclient.Client, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(c.rpc), nil
+}
+
 func (c *Chain) FundAccount(to common.Address, value *big.Int) error {
-	balance, err := c.clt.RPC().BalanceAt(context.Background(), c.clt.Addr(), nil)
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+
+	return c.FundAccountE(ctx, to, value)
+}
+
+// FundAccountE is like FundAccount but takes a context.
+func (c *Chain) FundAccountE(ctx context.Context, to common.Address, value *big.Int) error {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+	balance, err := clt.BalanceAt(ctx, c.account.Address(), nil)
 	if err != nil {
 		return err
 	}
 
 	log.Printf("funding account %s with %s", to.Hex(), value.String())
-	log.Printf("funder %s %s", c.clt.Addr().Hex(), balance.String())
+	log.Printf("funder %s %s", c.account.Address().Hex(), balance.String())
 
 	txn := &types.LegacyTx{
 		Value: value,
 		To:    &to,
 	}
-	result, err := c.clt.SendTransaction(txn)
+	hash, err := c.sendTransaction(ctx, c.account, txn, nil)
 	if err != nil {
 		return err
 	}
 
-	log.Printf("transaction hash: %s", result.Hash().Hex())
-	_, err = result.Wait()
+	c.logTx(hash)
+	receipt, err := c.WaitForReceipt(ctx, hash)
 	if err != nil {
 		return err
 	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return &TxError{Hash: hash, Receipt: receipt}
+	}
 	// check balance
-	balance, err = c.clt.RPC().BalanceAt(context.Background(), to, nil)
+	balance, err = clt.BalanceAt(ctx, to, nil)
 	if err != nil {
 		return err
 	}
 	if balance.Cmp(value) != 0 {
-		return errFundAccount
+		return ErrFundAccount
 	}
 	return nil
 }
@@ -0,0 +1,275 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Inspection is a SUAVE transaction or confidential request decoded with the
+// known artifacts.
+type Inspection struct {
+	Tx *types.Transaction
+
+	// Request is the confidential compute record of a confidential request or a
+	// SUAVE transaction, nil for other transactions.
+	Request *types.ConfidentialComputeRecord
+
+	// ConfidentialInputs are only carried by confidential requests, SUAVE
+	// transactions only include their hash in Request.
+	ConfidentialInputs []byte
+
+	// Sender is the account that signed the request. SignatureErr is not nil if
+	// the signature of the request, or the one of the kettle over a SUAVE
+	// transaction, is invalid.
+	Sender       common.Address
+	SignatureErr error
+
+	// Contract is the artifact whose ABI has the method called by the request,
+	// and Method and Args the decoded call. They are empty if no artifact matches.
+	Contract string
+	Method   *abi.Method
+	Args     []interface{}
+
+	// Callback is the callback calldata returned by the kettle in a SUAVE
+	// transaction, without the off-chain logs, and CallbackMethod and
+	// CallbackArgs the decoded callback.
+	Callback       []byte
+	CallbackMethod *abi.Method
+	CallbackArgs   []interface{}
+
+	// Receipt is nil if the transaction is not included yet. It is always nil
+	// for a confidential request, which is not included itself: its SUAVE
+	// transaction is, under the hash returned by the kettle.
+	Receipt *types.Receipt
+
+	// Events are the on-chain events of the receipt followed by the ones emitted
+	// off-chain and not re-emitted by the callback, for all the logs whose event
+	// is in a known artifact.
+	Events []*Event
+}
+
+// Inspector decodes SUAVE transactions and confidential requests of a chain
+// with the ABIs of a set of artifacts.
+type Inspector struct {
+	chain     *Chain
+	names     []string
+	artifacts map[string]*Artifact
+}
+
+// NewInspector returns an inspector of the transactions of chain. The
+// artifacts are tried in the order of their names.
+func NewInspector(chain *Chain, artifacts map[string]*Artifact) *Inspector {
+	names := make([]string, 0, len(artifacts))
+	for name := range artifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &Inspector{chain: chain, names: names, artifacts: artifacts}
+}
+
+// NewClientInspector returns an inspector of the transactions of the SUAVE node of
+// client. Unlike the chains of a Framework, it only reads from the node: it needs
+// neither an account nor a kettle.
+func NewClientInspector(client *rpc.Client, artifacts map[string]*Artifact) *Inspector {
+	chain := &Chain{rpc: client, suave: true, nonces: newNonceManager()}
+	chain.connect = func(ctx context.Context) error {
+		chainID, err := fetchChainID(ctx, client, 0)
+		if err != nil {
+			return err
+		}
+		chain.chainID = chainID
+		return nil
+	}
+	return NewInspector(chain, artifacts)
+}
+
+// LoadArtifacts loads all the contract artifacts of a Foundry output directory,
+// keyed by their path relative to dir, e.g. 'ofa-private.sol/OFAPrivate.json'.
+// Files that are not artifacts are skipped. If dir is empty, it loads the
+// directory of DefaultArtifactLoader.
+func LoadArtifacts(dir string) (map[string]*Artifact, error) {
+	if dir == "" {
+		dir = defaultArtifactsDir()
+	}
+	artifacts := map[string]*Artifact{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "build-info" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		artifact, err := ParseArtifact(data, "")
+		if err != nil || len(artifact.Abi.Methods)+len(artifact.Abi.Events) == 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		artifacts[filepath.ToSlash(rel)] = artifact
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the artifacts in %s: %w", dir, err)
+	}
+	return artifacts, nil
+}
+
+// InspectTx fetches the transaction hash and its receipt, if any, and decodes them.
+func (i *Inspector) InspectTx(ctx context.Context, hash common.Hash) (*Inspection, error) {
+	clt, err := i.chain.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txn, _, err := clt.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction %s: %w", hash.Hex(), err)
+	}
+	return i.inspect(ctx, txn)
+}
+
+// InspectRaw decodes a binary encoded transaction or confidential request, i.e.
+// one written by WriteRawTransaction. The receipt of a transaction is fetched if
+// it was included.
+func (i *Inspector) InspectRaw(ctx context.Context, raw []byte) (*Inspection, error) {
+	txn := new(types.Transaction)
+	if err := txn.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	return i.inspect(ctx, txn)
+}
+
+func (i *Inspector) inspect(ctx context.Context, txn *types.Transaction) (*Inspection, error) {
+	signer, err := i.chain.signer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Inspection{Tx: txn}
+	res.Sender, res.SignatureErr = types.Sender(signer, txn)
+
+	calldata := txn.Data()
+	switch txn.Type() {
+	case types.ConfidentialComputeRequestTxType:
+		request, _ := types.CastTxInner[*types.ConfidentialComputeRequest](txn)
+		res.Request = &request.ConfidentialComputeRecord
+		res.ConfidentialInputs = request.ConfidentialInputs
+	case types.SuaveTxType:
+		suaveTx, _ := types.CastTxInner[*types.SuaveTransaction](txn)
+		res.Request = &suaveTx.ConfidentialComputeRequest
+		calldata = res.Request.Data
+	}
+	res.Contract, res.Method, res.Args = i.decodeCall(calldata, "")
+
+	var offchain []*types.Log
+	if txn.Type() == types.SuaveTxType {
+		res.Callback, offchain = splitOffchainLogs(txn.Data())
+		_, res.CallbackMethod, res.CallbackArgs = i.decodeCall(res.Callback, res.Contract)
+	}
+
+	if txn.Type() != types.ConfidentialComputeRequestTxType {
+		clt, err := i.chain.client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.Receipt, err = clt.TransactionReceipt(ctx, txn.Hash())
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("failed to fetch the receipt of %s: %w", txn.Hash().Hex(), err)
+		}
+	}
+
+	for idx, log := range offchain {
+		log.TxHash = txn.Hash()
+		log.Index = uint(idx)
+		if res.Receipt != nil {
+			log.TxIndex = res.Receipt.TransactionIndex
+			log.BlockHash = res.Receipt.BlockHash
+			log.BlockNumber = res.Receipt.BlockNumber.Uint64()
+		}
+	}
+
+	var reemitted map[*types.Log]bool
+	if res.Receipt != nil {
+		reemitted, offchain = matchOffchainLogs(res.Receipt.Logs, offchain)
+		for _, log := range res.Receipt.Logs {
+			if event := i.decodeLog(log, res.Contract); event != nil {
+				event.Offchain = reemitted[log]
+				res.Events = append(res.Events, event)
+			}
+		}
+	}
+	for _, log := range offchain {
+		if event := i.decodeLog(log, res.Contract); event != nil {
+			event.Offchain = true
+			res.Events = append(res.Events, event)
+		}
+	}
+	return res, nil
+}
+
+// order returns the artifact names with preferred first.
+func (i *Inspector) order(preferred string) []string {
+	if _, ok := i.artifacts[preferred]; !ok {
+		return i.names
+	}
+	return append([]string{preferred}, i.names...)
+}
+
+// decodeCall decodes calldata with the first artifact that has its method.
+func (i *Inspector) decodeCall(calldata []byte, preferred string) (string, *abi.Method, []interface{}) {
+	if len(calldata) < 4 {
+		return "", nil, nil
+	}
+	for _, name := range i.order(preferred) {
+		method, err := i.artifacts[name].Abi.MethodById(calldata[:4])
+		if err != nil {
+			continue
+		}
+		args, err := method.Inputs.Unpack(calldata[4:])
+		if err != nil {
+			continue
+		}
+		return name, method, args
+	}
+	return "", nil, nil
+}
+
+// decodeLog decodes log with the first artifact that has its event, nil if none.
+func (i *Inspector) decodeLog(log *types.Log, preferred string) *Event {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	for _, name := range i.order(preferred) {
+		event, err := i.artifacts[name].Abi.EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+		if decoded, err := decodeLog(*event, log); err == nil {
+			return decoded
+		}
+	}
+	return nil
+}
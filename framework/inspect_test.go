@@ -0,0 +1,188 @@
+package framework
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspector(t *testing.T) {
+	contract, node := newTestRequestContract(t)
+	ctx := context.Background()
+
+	pending, err := contract.SendConfidentialRequestAsync(ctx, "submit", []interface{}{uint64(7)}, []byte{0x01})
+	require.NoError(t, err)
+	submitted := contract.Abi.Events["Submitted"]
+	node.mu.Lock()
+	node.receipts[pending.Hash].Logs = []*types.Log{{
+		Address: contract.addr,
+		Topics:  []common.Hash{submitted.ID, common.BigToHash(common.Big1)},
+		TxHash:  pending.Hash,
+	}}
+	node.mu.Unlock()
+
+	unsent, err := contract.SendConfidentialRequestAsync(ctx, "submit", []interface{}{uint64(8)}, []byte{0x02}, WithNoSend())
+	require.NoError(t, err)
+	rawRequest, err := unsent.Request.MarshalBinary()
+	require.NoError(t, err)
+
+	// a SUAVE transaction signed by another key than the kettle of its request
+	suaveTx, _, err := contract.chain.RPC().TransactionByHash(ctx, pending.Hash)
+	require.NoError(t, err)
+	inner, _ := types.CastTxInner[*types.SuaveTransaction](suaveTx)
+	forged, err := types.SignTx(types.NewTx(inner), types.NewSuaveSigner(node.chainID), GeneratePrivKey().Priv)
+	require.NoError(t, err)
+	rawForged, err := forged.MarshalBinary()
+	require.NoError(t, err)
+
+	// the inspector only reads from the node, which serves no kettle API
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	client := rpc.DialInProc(server)
+	defer client.Close()
+	artifacts := map[string]*Artifact{"Test.sol/Test.json": {Abi: contract.Abi}}
+
+	cases := []struct {
+		name      string
+		artifacts map[string]*Artifact
+		inspect   func(i *Inspector) (*Inspection, error)
+		check     func(t *testing.T, res *Inspection)
+		wantErr   bool
+	}{
+		{
+			name:      "SUAVE transaction",
+			artifacts: artifacts,
+			inspect:   func(i *Inspector) (*Inspection, error) { return i.InspectTx(ctx, pending.Hash) },
+			check: func(t *testing.T, res *Inspection) {
+				require.NoError(t, res.SignatureErr)
+				require.Equal(t, "Test.sol/Test.json", res.Contract)
+				require.Equal(t, "submit", res.Method.Name)
+				require.Equal(t, []interface{}{uint64(7)}, res.Args)
+				require.Equal(t, "onSubmit", res.CallbackMethod.Name)
+				require.Equal(t, []interface{}{uint64(7)}, res.CallbackArgs)
+				require.Nil(t, res.ConfidentialInputs)
+				require.NotNil(t, res.Receipt)
+				require.Len(t, res.Events, 1)
+				require.Equal(t, "Submitted", res.Events[0].Name)
+				require.False(t, res.Events[0].Offchain)
+			},
+		},
+		{
+			name:      "confidential request",
+			artifacts: artifacts,
+			inspect:   func(i *Inspector) (*Inspection, error) { return i.InspectRaw(ctx, rawRequest) },
+			check: func(t *testing.T, res *Inspection) {
+				require.NoError(t, res.SignatureErr)
+				require.Equal(t, contract.account.Address(), res.Sender)
+				require.Equal(t, node.kettle.Address(), res.Request.KettleAddress)
+				require.Equal(t, []byte{0x02}, res.ConfidentialInputs)
+				require.Equal(t, "submit", res.Method.Name)
+				require.Equal(t, []interface{}{uint64(8)}, res.Args)
+				require.Nil(t, res.Receipt)
+				require.Zero(t, node.receiptLookups(unsent.Request.Hash()))
+			},
+		},
+		{
+			name:    "unknown contract",
+			inspect: func(i *Inspector) (*Inspection, error) { return i.InspectTx(ctx, pending.Hash) },
+			check: func(t *testing.T, res *Inspection) {
+				require.Empty(t, res.Contract)
+				require.Nil(t, res.Method)
+				require.Nil(t, res.CallbackMethod)
+				require.Empty(t, res.Events)
+			},
+		},
+		{
+			name:      "not signed by the kettle",
+			artifacts: artifacts,
+			inspect:   func(i *Inspector) (*Inspection, error) { return i.InspectRaw(ctx, rawForged) },
+			check: func(t *testing.T, res *Inspection) {
+				require.Error(t, res.SignatureErr)
+				require.Nil(t, res.Receipt)
+			},
+		},
+		{
+			name:    "unknown hash",
+			inspect: func(i *Inspector) (*Inspection, error) { return i.InspectTx(ctx, common.Hash{0x01}) },
+			wantErr: true,
+		},
+		{
+			name:    "malformed raw transaction",
+			inspect: func(i *Inspector) (*Inspection, error) { return i.InspectRaw(ctx, []byte{0x01, 0x02}) },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.inspect(NewClientInspector(client, tc.artifacts))
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, res)
+		})
+	}
+}
+
+func TestLoadArtifacts(t *testing.T) {
+	artifact := `{"abi": ` + testRequestAbi + `, "bytecode": {"object": "0x6080"}, "deployedBytecode": {"object": "0x6080"}}`
+
+	cases := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "artifacts",
+			files: map[string]string{
+				"Test.sol/Test.json":   artifact,
+				"Other.sol/Other.json": artifact,
+			},
+			want: []string{"Other.sol/Other.json", "Test.sol/Test.json"},
+		},
+		{
+			name: "skipped files",
+			files: map[string]string{
+				"Test.sol/Test.json":     artifact,
+				"build-info/build.json":  artifact,
+				"Test.sol/Test.metadata": artifact,
+				"Test.sol/Invalid.json":  "not json",
+				"Empty.sol/Empty.json":   `{"abi": [], "bytecode": {"object": "0x"}}`,
+			},
+			want: []string{"Test.sol/Test.json"},
+		},
+		{
+			name: "empty directory",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, content := range tc.files {
+				path = filepath.Join(dir, path)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			}
+
+			got, err := LoadArtifacts(dir)
+			require.NoError(t, err)
+			names := make([]string, 0, len(got))
+			for name := range got {
+				names = append(names, name)
+			}
+			require.ElementsMatch(t, tc.want, names)
+		})
+	}
+
+	_, err := LoadArtifacts(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,190 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Kettle is a kettle of the SUAVE chain and the endpoint it is reached at.
+type Kettle struct {
+	Address common.Address
+	URL     string
+
+	rpc *rpc.Client
+}
+
+// KettlePolicy selects the kettles the confidential requests are sent to.
+type KettlePolicy interface {
+	// Kettles returns the kettles to try for a request to contract, in order.
+	// A request fails over to the next kettle when one cannot be reached.
+	Kettles(contract common.Address, kettles []*Kettle) []*Kettle
+}
+
+// FailoverPolicy sends all the requests to the first configured kettle and
+// fails over to the next ones in order. It is the default policy.
+func FailoverPolicy() KettlePolicy {
+	return failoverPolicy{}
+}
+
+type failoverPolicy struct{}
+
+func (failoverPolicy) Kettles(contract common.Address, kettles []*Kettle) []*Kettle {
+	return kettles
+}
+
+// RoundRobinPolicy spreads the requests over the kettles in turn.
+func RoundRobinPolicy() KettlePolicy {
+	return &roundRobinPolicy{}
+}
+
+type roundRobinPolicy struct {
+	next atomic.Uint64
+}
+
+func (p *roundRobinPolicy) Kettles(contract common.Address, kettles []*Kettle) []*Kettle {
+	return rotate(kettles, int(p.next.Add(1)-1))
+}
+
+// StickyPolicy sends all the requests to a contract to the same kettle, so
+// that they share its confidential store. The contracts are assigned to the
+// kettles in turn and fail over to the next kettles without being reassigned.
+func StickyPolicy() KettlePolicy {
+	return &stickyPolicy{assigned: map[common.Address]int{}}
+}
+
+type stickyPolicy struct {
+	mu       sync.Mutex
+	next     int
+	assigned map[common.Address]int
+}
+
+func (p *stickyPolicy) Kettles(contract common.Address, kettles []*Kettle) []*Kettle {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idx, ok := p.assigned[contract]
+	if !ok {
+		idx = p.next
+		p.assigned[contract] = idx
+		p.next++
+	}
+	return rotate(kettles, idx)
+}
+
+// rotate returns kettles starting at the kettle idx modulo their number.
+func rotate(kettles []*Kettle, idx int) []*Kettle {
+	if len(kettles) == 0 {
+		return nil
+	}
+	idx %= len(kettles)
+	return append(append([]*Kettle{}, kettles[idx:]...), kettles[:idx]...)
+}
+
+// resolveKettles returns the kettles reached at the endpoint url with client.
+// An endpoint that runs several kettles yields one Kettle per address.
+func resolveKettles(ctx context.Context, url string, client *rpc.Client) ([]*Kettle, error) {
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_kettleAddress"); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrKettleAddress, err)
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("%w: kettle reported no addresses", ErrKettleAddress)
+	}
+
+	kettles := make([]*Kettle, len(accounts))
+	for i, addr := range accounts {
+		kettles[i] = &Kettle{Address: addr, URL: url, rpc: client}
+	}
+	return kettles, nil
+}
+
+// selectKettleAddresses keeps the kettles whose address is in addrs, in the
+// order of addrs. It keeps all of them if addrs is empty.
+func selectKettleAddresses(kettles []*Kettle, addrs []string) ([]*Kettle, error) {
+	if len(addrs) == 0 {
+		return kettles, nil
+	}
+	var selected []*Kettle
+	for _, addr := range addrs {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("%w: invalid kettle address '%s'", ErrConfig, addr)
+		}
+		idx := slices.IndexFunc(kettles, func(k *Kettle) bool { return k.Address == common.HexToAddress(addr) })
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: kettle %s not reported by the kettle endpoints", ErrKettleAddress, addr)
+		}
+		selected = append(selected, kettles[idx])
+	}
+	return selected, nil
+}
+
+// Kettles returns the kettles of the chain, in the configured order. Like RPC, it
+// connects the chain if needed and panics if it cannot.
+func (c *Chain) Kettles() []*Kettle {
+	c.mustConnect()
+	return c.kettles
+}
+
+// KettlesE is like Kettles but returns an error instead of panicking.
+func (c *Chain) KettlesE(ctx context.Context) ([]*Kettle, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return c.kettles, nil
+}
+
+// selectKettles returns the kettles to try for a request to contract. A kettle
+// selected explicitly is the only candidate, it is reached through the chain
+// endpoint if it is not one of the configured kettles.
+func (c *Chain) selectKettles(contract common.Address, selected *common.Address) []*Kettle {
+	if selected != nil {
+		for _, kettle := range c.kettles {
+			if kettle.Address == *selected {
+				return []*Kettle{kettle}
+			}
+		}
+		return []*Kettle{{Address: *selected, rpc: c.rpc}}
+	}
+	if kettles := c.policy.Kettles(contract, c.kettles); len(kettles) > 0 {
+		return kettles
+	}
+	return []*Kettle{{Address: c.kettleAddr, rpc: c.rpc}}
+}
+
+// kettleRPC returns the endpoint of the kettle addr, the chain endpoint if it
+// is not one of the configured kettles.
+func (c *Chain) kettleRPC(addr common.Address) *rpc.Client {
+	for _, kettle := range c.kettles {
+		if kettle.Address == addr {
+			return kettle.rpc
+		}
+	}
+	return c.rpc
+}
+
+// txRPC returns the endpoint to send txn to: the one of its kettle for
+// confidential requests, the chain endpoint otherwise.
+func (c *Chain) txRPC(txn *types.Transaction) *rpc.Client {
+	if request, ok := types.CastTxInner[*types.ConfidentialComputeRequest](txn); ok {
+		return c.kettleRPC(request.KettleAddress)
+	}
+	return c.rpc
+}
+
+// isUnavailable reports whether err means that the endpoint could not be reached,
+// as opposed to an error returned by the node, so the next kettle can be tried.
+func isUnavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
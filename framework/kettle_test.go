@@ -0,0 +1,237 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func testKettles(n int) []*Kettle {
+	kettles := make([]*Kettle, n)
+	for i := range kettles {
+		kettles[i] = &Kettle{Address: common.Address{byte(i + 1)}}
+	}
+	return kettles
+}
+
+// kettleIndexes returns the positions of kettles in all, to compare orders.
+func kettleIndexes(all, kettles []*Kettle) []int {
+	idx := make([]int, len(kettles))
+	for i, kettle := range kettles {
+		for j, k := range all {
+			if k == kettle {
+				idx[i] = j
+			}
+		}
+	}
+	return idx
+}
+
+func TestRotate(t *testing.T) {
+	kettles := testKettles(3)
+
+	cases := []struct {
+		idx  int
+		want []int
+	}{
+		{idx: 0, want: []int{0, 1, 2}},
+		{idx: 1, want: []int{1, 2, 0}},
+		{idx: 2, want: []int{2, 0, 1}},
+		{idx: 3, want: []int{0, 1, 2}},
+		{idx: 7, want: []int{1, 2, 0}},
+	}
+
+	for _, tc := range cases {
+		got := rotate(kettles, tc.idx)
+		require.Equal(t, tc.want, kettleIndexes(kettles, got), "idx %d", tc.idx)
+	}
+
+	require.Nil(t, rotate(nil, 1))
+
+	// the kettles are not modified
+	require.Equal(t, []int{0, 1, 2}, kettleIndexes(kettles, kettles))
+}
+
+func TestKettlePolicies(t *testing.T) {
+	kettles := testKettles(3)
+	a, b, c := common.Address{0xa}, common.Address{0xb}, common.Address{0xc}
+
+	cases := []struct {
+		name      string
+		policy    KettlePolicy
+		contracts []common.Address
+		// want are the first kettles of the successive requests
+		want []int
+	}{
+		{
+			name:      "failover",
+			policy:    FailoverPolicy(),
+			contracts: []common.Address{a, b, a, c},
+			want:      []int{0, 0, 0, 0},
+		},
+		{
+			name:      "round robin",
+			policy:    RoundRobinPolicy(),
+			contracts: []common.Address{a, a, b, a, c},
+			want:      []int{0, 1, 2, 0, 1},
+		},
+		{
+			name:      "sticky",
+			policy:    StickyPolicy(),
+			contracts: []common.Address{a, b, a, c, b, a},
+			want:      []int{0, 1, 0, 2, 1, 0},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []int
+			for _, contract := range tc.contracts {
+				order := tc.policy.Kettles(contract, kettles)
+				require.Len(t, order, len(kettles), "every kettle is a failover candidate")
+				got = append(got, kettleIndexes(kettles, order)[0])
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSelectKettleAddresses(t *testing.T) {
+	kettles := testKettles(3)
+
+	cases := []struct {
+		name    string
+		addrs   []string
+		want    []int
+		wantErr error
+	}{
+		{name: "all", want: []int{0, 1, 2}},
+		{name: "in the given order", addrs: []string{kettles[2].Address.Hex(), kettles[0].Address.Hex()}, want: []int{2, 0}},
+		{name: "unknown kettle", addrs: []string{common.Address{0xff}.Hex()}, wantErr: ErrKettleAddress},
+		{name: "invalid address", addrs: []string{"0x123"}, wantErr: ErrConfig},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectKettleAddresses(kettles, tc.addrs)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, kettleIndexes(kettles, got))
+		})
+	}
+}
+
+func TestChainSelectKettles(t *testing.T) {
+	kettles := testKettles(2)
+	chainRPC := &rpc.Client{}
+	chain := &Chain{rpc: chainRPC, kettles: kettles, policy: RoundRobinPolicy(), kettleAddr: kettles[0].Address}
+	contract := common.Address{0xa}
+
+	require.Equal(t, []int{0, 1}, kettleIndexes(kettles, chain.selectKettles(contract, nil)))
+	require.Equal(t, []int{1, 0}, kettleIndexes(kettles, chain.selectKettles(contract, nil)))
+
+	selected := chain.selectKettles(contract, &kettles[1].Address)
+	require.Equal(t, []*Kettle{kettles[1]}, selected)
+
+	// a kettle that is not configured is reached through the chain endpoint
+	unknown := common.Address{0xff}
+	selected = chain.selectKettles(contract, &unknown)
+	require.Len(t, selected, 1)
+	require.Equal(t, unknown, selected[0].Address)
+	require.Same(t, chainRPC, selected[0].rpc)
+
+	// without configured kettles, the requests go to the kettle of the chain
+	chain = &Chain{rpc: chainRPC, policy: FailoverPolicy(), kettleAddr: kettles[0].Address}
+	selected = chain.selectKettles(contract, nil)
+	require.Len(t, selected, 1)
+	require.Equal(t, kettles[0].Address, selected[0].Address)
+}
+
+func TestChainKettles(t *testing.T) {
+	kettles := testKettles(2)
+	connects := 0
+	chain := &Chain{}
+	chain.connect = func(ctx context.Context) error {
+		connects++
+		chain.kettles = kettles
+		return nil
+	}
+
+	// the kettles are only known once the chain is connected
+	require.Equal(t, kettles, chain.Kettles())
+	got, err := chain.KettlesE(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, kettles, got)
+	require.Equal(t, 1, connects)
+
+	dialErr := errors.New("connection refused")
+	chain = &Chain{connect: func(ctx context.Context) error { return dialErr }}
+	_, err = chain.KettlesE(context.Background())
+	require.ErrorIs(t, err, dialErr)
+	require.Panics(t, func() { chain.Kettles() })
+}
+
+type testKettleAPI struct {
+	addrs []common.Address
+}
+
+func (a *testKettleAPI) KettleAddress() []common.Address {
+	return a.addrs
+}
+
+func TestResolveKettles(t *testing.T) {
+	cases := []struct {
+		name    string
+		addrs   []common.Address
+		wantErr bool
+	}{
+		{name: "one kettle", addrs: []common.Address{{0x1}}},
+		{name: "several kettles", addrs: []common.Address{{0x1}, {0x2}}},
+		{name: "no kettle", addrs: []common.Address{}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := rpc.NewServer()
+			require.NoError(t, server.RegisterName("eth", &testKettleAPI{addrs: tc.addrs}))
+			client := rpc.DialInProc(server)
+			defer client.Close()
+
+			kettles, err := resolveKettles(context.Background(), "http://kettle", client)
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrKettleAddress)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, kettles, len(tc.addrs))
+			for i, kettle := range kettles {
+				require.Equal(t, tc.addrs[i], kettle.Address)
+				require.Equal(t, "http://kettle", kettle.URL)
+				require.Same(t, client, kettle.rpc)
+			}
+		})
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.True(t, isUnavailable(context.Background(), errors.New("connection refused")))
+	require.False(t, isUnavailable(context.Background(), &testRPCError{code: -32000}))
+	require.False(t, isUnavailable(canceled, errors.New("connection refused")))
+}
+
+type testRPCError struct {
+	code int
+}
+
+func (e *testRPCError) Error() string  { return "rpc error" }
+func (e *testRPCError) ErrorCode() int { return e.code }
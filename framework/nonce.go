@@ -0,0 +1,130 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nonceManager allocates the nonces of the accounts locally so that several
+// transactions and confidential requests of an account can be in flight at the
+// same time. An account is synced with the pending nonce of the chain the first
+// time it is used and every time a send fails with a nonce it cannot release.
+type nonceManager struct {
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+}
+
+func newNonceManager() *nonceManager {
+	return &nonceManager{nonces: map[common.Address]uint64{}}
+}
+
+// next returns the next nonce of addr. sync fetches the pending nonce of the
+// account from the chain if it is not tracked yet. The lock is not held while
+// syncing so that a slow node does not block the other accounts.
+func (n *nonceManager) next(ctx context.Context, addr common.Address, sync func(context.Context, common.Address) (uint64, error)) (uint64, error) {
+	if nonce, ok := n.allocate(addr); ok {
+		return nonce, nil
+	}
+	synced, err := sync(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// a concurrent call may have synced the account in the meantime
+	nonce, ok := n.nonces[addr]
+	if !ok {
+		nonce = synced
+	}
+	n.nonces[addr] = nonce + 1
+	return nonce, nil
+}
+
+// allocate returns the next nonce of addr if it is tracked.
+func (n *nonceManager) allocate(addr common.Address) (uint64, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	nonce, ok := n.nonces[addr]
+	if ok {
+		n.nonces[addr] = nonce + 1
+	}
+	return nonce, ok
+}
+
+// release returns a nonce that was not used. If a later nonce was already
+// allocated, the account is resynced with the chain on the next send.
+func (n *nonceManager) release(addr common.Address, nonce uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if next, ok := n.nonces[addr]; ok && next == nonce+1 {
+		n.nonces[addr] = nonce
+	} else {
+		delete(n.nonces, addr)
+	}
+}
+
+// reset drops the nonce of addr to resync it with the chain on the next send.
+func (n *nonceManager) reset(addr common.Address) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.nonces, addr)
+}
+
+// isNonceError reports whether the node rejected a transaction because of its nonce.
+func isNonceError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// sendWithNonce allocates a nonce of addr and calls send with it. If send fails the
+// nonce is released, and if the node rejected the nonce the account is resynced
+// and send is retried once.
+func (c *Chain) sendWithNonce(ctx context.Context, addr common.Address, send func(nonce uint64) (common.Hash, error)) (common.Hash, error) {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for attempt := 0; ; attempt++ {
+		nonce, err := c.nonces.next(ctx, addr, clt.PendingNonceAt)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		hash, err := send(nonce)
+		if err == nil {
+			return hash, nil
+		}
+
+		if !isNonceError(err) {
+			c.nonces.release(addr, nonce)
+			return common.Hash{}, err
+		}
+		c.nonces.reset(addr)
+		if attempt > 0 {
+			return common.Hash{}, err
+		}
+	}
+}
+
+// ResetNonce makes the chain fetch the nonce of addr again on its next send. Use it
+// after sending transactions of the account without the framework, i.e. with the
+// SDK contract of Contract.Raw.
+func (c *Chain) ResetNonce(addr common.Address) {
+	c.nonces.reset(addr)
+}
+
+// ReleaseNonce returns the nonce of a transaction of addr signed with SignTx that
+// will not be sent, so that the next send of the account reuses it. If a later
+// nonce was allocated since, the account is resynced with the chain instead.
+func (c *Chain) ReleaseNonce(addr common.Address, nonce uint64) {
+	c.nonces.release(addr, nonce)
+}
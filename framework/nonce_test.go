@@ -0,0 +1,220 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testNonceSync returns a sync function that reports pending as the pending
+// nonce of the chain and counts its calls.
+func testNonceSync(pending *uint64, calls *int) func(context.Context, common.Address) (uint64, error) {
+	return func(context.Context, common.Address) (uint64, error) {
+		*calls++
+		return *pending, nil
+	}
+}
+
+func TestNonceManager(t *testing.T) {
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+	cases := []struct {
+		name string
+		// run allocates nonces with next and returns them
+		run       func(n *nonceManager, next func() uint64) []uint64
+		want      []uint64
+		wantSyncs int
+	}{
+		{
+			name: "sequential",
+			run: func(n *nonceManager, next func() uint64) []uint64 {
+				return []uint64{next(), next(), next()}
+			},
+			want:      []uint64{5, 6, 7},
+			wantSyncs: 1,
+		},
+		{
+			name: "release the last nonce",
+			run: func(n *nonceManager, next func() uint64) []uint64 {
+				first := next()
+				n.release(addr, first)
+				return []uint64{first, next()}
+			},
+			want:      []uint64{5, 5},
+			wantSyncs: 1,
+		},
+		{
+			name: "release an earlier nonce resyncs",
+			run: func(n *nonceManager, next func() uint64) []uint64 {
+				first, second := next(), next()
+				n.release(addr, first)
+				return []uint64{first, second, next()}
+			},
+			want:      []uint64{5, 6, 5},
+			wantSyncs: 2,
+		},
+		{
+			name: "release an untracked nonce",
+			run: func(n *nonceManager, next func() uint64) []uint64 {
+				n.release(addr, 3)
+				return []uint64{next()}
+			},
+			want:      []uint64{5},
+			wantSyncs: 1,
+		},
+		{
+			name: "reset resyncs",
+			run: func(n *nonceManager, next func() uint64) []uint64 {
+				first := next()
+				n.reset(addr)
+				return []uint64{first, next()}
+			},
+			want:      []uint64{5, 5},
+			wantSyncs: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := newNonceManager()
+			pending, syncs := uint64(5), 0
+			next := func() uint64 {
+				nonce, err := n.next(context.Background(), addr, testNonceSync(&pending, &syncs))
+				require.NoError(t, err)
+				return nonce
+			}
+			require.Equal(t, tc.want, tc.run(n, next))
+			require.Equal(t, tc.wantSyncs, syncs)
+		})
+	}
+}
+
+func TestNonceManagerAccounts(t *testing.T) {
+	n := newNonceManager()
+	a, b := common.Address{0x0a}, common.Address{0x0b}
+	syncNonce := func(ctx context.Context, addr common.Address) (uint64, error) {
+		if addr == a {
+			return 10, nil
+		}
+		return 20, nil
+	}
+
+	nonce, err := n.next(context.Background(), a, syncNonce)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), nonce)
+	nonce, err = n.next(context.Background(), b, syncNonce)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), nonce)
+
+	n.reset(a)
+	nonce, err = n.next(context.Background(), b, syncNonce)
+	require.NoError(t, err)
+	require.Equal(t, uint64(21), nonce)
+}
+
+func TestNonceManagerSyncError(t *testing.T) {
+	n := newNonceManager()
+	addr := common.Address{0x0a}
+	syncErr := errors.New("node unavailable")
+
+	_, err := n.next(context.Background(), addr, func(context.Context, common.Address) (uint64, error) {
+		return 0, syncErr
+	})
+	require.ErrorIs(t, err, syncErr)
+
+	// a failed sync is not tracked
+	nonce, err := n.next(context.Background(), addr, func(context.Context, common.Address) (uint64, error) {
+		return 3, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce)
+}
+
+func TestNonceManagerConcurrent(t *testing.T) {
+	n := newNonceManager()
+	addr := common.Address{0x0a}
+	syncNonce := func(context.Context, common.Address) (uint64, error) { return 0, nil }
+
+	const senders = 50
+	nonces := make(chan uint64, senders)
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := n.next(context.Background(), addr, syncNonce)
+			if err == nil {
+				nonces <- nonce
+			}
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := map[uint64]bool{}
+	for nonce := range nonces {
+		require.False(t, seen[nonce], "nonce %d allocated twice", nonce)
+		seen[nonce] = true
+	}
+	require.Len(t, seen, senders)
+}
+
+func TestNonceManagerSlowSync(t *testing.T) {
+	n := newNonceManager()
+	slow, fast := common.Address{0x0a}, common.Address{0x0b}
+
+	syncing, unblock := make(chan struct{}), make(chan struct{})
+	syncNonce := func(ctx context.Context, addr common.Address) (uint64, error) {
+		if addr == slow {
+			close(syncing)
+			<-unblock
+			return 10, nil
+		}
+		return 20, nil
+	}
+
+	done := make(chan uint64)
+	go func() {
+		nonce, _ := n.next(context.Background(), slow, syncNonce)
+		done <- nonce
+	}()
+	<-syncing
+
+	// the other accounts are not blocked by the pending sync
+	nonce, err := n.next(context.Background(), fast, syncNonce)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), nonce)
+
+	// nor the account itself once it is tracked by another call
+	nonce, err = n.next(context.Background(), slow, func(context.Context, common.Address) (uint64, error) {
+		return 10, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), nonce)
+
+	// the late sync does not hand out the same nonce again
+	close(unblock)
+	require.Equal(t, uint64(11), <-done)
+}
+
+func TestIsNonceError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{err: errors.New("nonce too low: next nonce 5, tx nonce 4"), want: true},
+		{err: errors.New("nonce too high"), want: true},
+		{err: errors.New("replacement transaction underpriced"), want: true},
+		{err: errors.New("insufficient funds for gas * price + value"), want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			require.Equal(t, tc.want, isNonceError(tc.err))
+		})
+	}
+}
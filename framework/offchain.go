@@ -0,0 +1,101 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	suave "github.com/ethereum/go-ethereum/suave/core"
+)
+
+// offchainLogsMagic separates the callback calldata from the off-chain logs
+// that the kettle appends to the result of a confidential request.
+var offchainLogsMagic = []byte{0x54, 0x35, 0x43}
+
+// DecodeOffchainLogs decodes the logs emitted during the confidential computation
+// from the calldata of a callback. It returns nil if the calldata has no logs,
+// i.e. the confidential computation did not emit any.
+func DecodeOffchainLogs(calldata []byte) []*types.Log {
+	_, logs := splitOffchainLogs(calldata)
+	return logs
+}
+
+// matchOffchainLogs matches the off-chain logs with the on-chain logs of the
+// receipt that re-emit them, in order. It returns the re-emitted on-chain logs
+// and the off-chain logs that were not re-emitted.
+func matchOffchainLogs(onchain, offchain []*types.Log) (map[*types.Log]bool, []*types.Log) {
+	reemitted := map[*types.Log]bool{}
+	var rest []*types.Log
+	next := 0
+	for _, log := range offchain {
+		idx := -1
+		for i := next; i < len(onchain); i++ {
+			if sameLog(onchain[i], log) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			rest = append(rest, log)
+			continue
+		}
+		reemitted[onchain[idx]] = true
+		next = idx + 1
+	}
+	return reemitted, rest
+}
+
+func sameLog(a, b *types.Log) bool {
+	return a.Address == b.Address && slices.Equal(a.Topics, b.Topics) && bytes.Equal(a.Data, b.Data)
+}
+
+// splitOffchainLogs splits the calldata of a callback into the calldata of the
+// callback method and the off-chain logs appended by the kettle. The kettle only
+// appends them if the confidential computation emitted logs, so calldata whose
+// magic bytes are not followed by encoded logs is returned as is.
+func splitOffchainLogs(calldata []byte) ([]byte, []*types.Log) {
+	// the callback calldata is a selector followed by 32-byte words
+	for offset := 4; offset+len(offchainLogsMagic) <= len(calldata); offset += 32 {
+		if !bytes.Equal(calldata[offset:offset+len(offchainLogsMagic)], offchainLogsMagic) {
+			continue
+		}
+		var result suave.ExecResult
+		if err := result.DecodeABI(calldata[offset+len(offchainLogsMagic):]); err != nil {
+			// the magic bytes can also be part of an argument
+			continue
+		}
+		return calldata[:offset], result.Logs
+	}
+	return calldata, nil
+}
+
+// OffchainLogs returns the logs emitted off-chain by the confidential request
+// of the receipt. The logs carry the transaction and block of the receipt and
+// are indexed in the order they were emitted. The kettle appends them to the
+// callback calldata, the emitOffchainLogs modifier of suave-std also re-emits
+// them on-chain.
+func (c *Chain) OffchainLogs(ctx context.Context, receipt *types.Receipt) ([]*types.Log, error) {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txn, _, err := clt.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction %s: %w", receipt.TxHash.Hex(), err)
+	}
+	if txn.Type() != types.SuaveTxType {
+		return nil, nil
+	}
+
+	logs := DecodeOffchainLogs(txn.Data())
+	for i, log := range logs {
+		log.TxHash = receipt.TxHash
+		log.TxIndex = receipt.TransactionIndex
+		log.BlockHash = receipt.BlockHash
+		log.BlockNumber = receipt.BlockNumber.Uint64()
+		log.Index = uint(i)
+	}
+	return logs, nil
+}
@@ -0,0 +1,116 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	suave "github.com/ethereum/go-ethereum/suave/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitOffchainLogs(t *testing.T) {
+	logs := []*types.Log{
+		{Address: common.Address{0x01}, Topics: []common.Hash{{0xaa}, {0xbb}}, Data: []byte{0x01, 0x02}},
+		{Address: common.Address{0x02}, Topics: []common.Hash{}, Data: []byte{}},
+	}
+	encoded, err := (&suave.ExecResult{Logs: logs}).EncodeABI()
+	require.NoError(t, err)
+	empty, err := (&suave.ExecResult{}).EncodeABI()
+	require.NoError(t, err)
+
+	// a selector and a word argument
+	callback := append([]byte{0x12, 0x34, 0x56, 0x78}, common.Hash{0x01}.Bytes()...)
+	// an argument that starts with the magic bytes
+	magicArg := append([]byte{0x12, 0x34, 0x56, 0x78}, append(append([]byte{}, offchainLogsMagic...), make([]byte, 29)...)...)
+
+	join := func(parts ...[]byte) []byte {
+		var res []byte
+		for _, part := range parts {
+			res = append(res, part...)
+		}
+		return res
+	}
+
+	cases := []struct {
+		name         string
+		calldata     []byte
+		wantCallback []byte
+		wantLogs     []*types.Log
+	}{
+		{
+			name:         "no logs",
+			calldata:     callback,
+			wantCallback: callback,
+		},
+		{
+			name:         "selector only",
+			calldata:     callback[:4],
+			wantCallback: callback[:4],
+		},
+		{
+			name:         "logs",
+			calldata:     join(callback, offchainLogsMagic, encoded),
+			wantCallback: callback,
+			wantLogs:     logs,
+		},
+		{
+			name:         "empty logs",
+			calldata:     join(callback, offchainLogsMagic, empty),
+			wantCallback: callback,
+			wantLogs:     []*types.Log{},
+		},
+		{
+			name:         "magic bytes in an argument",
+			calldata:     join(magicArg, offchainLogsMagic, encoded),
+			wantCallback: magicArg,
+			wantLogs:     logs,
+		},
+		{
+			name:         "magic bytes in an argument without logs",
+			calldata:     magicArg,
+			wantCallback: magicArg,
+		},
+		{
+			name:         "truncated logs",
+			calldata:     join(callback, offchainLogsMagic, encoded[:len(encoded)-10]),
+			wantCallback: join(callback, offchainLogsMagic, encoded[:len(encoded)-10]),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotCallback, gotLogs := splitOffchainLogs(tc.calldata)
+			require.Equal(t, tc.wantCallback, gotCallback)
+			require.Len(t, gotLogs, len(tc.wantLogs))
+			for i, log := range gotLogs {
+				require.True(t, sameLog(tc.wantLogs[i], log), "log %d", i)
+			}
+		})
+	}
+}
+
+func TestMatchOffchainLogs(t *testing.T) {
+	a := &types.Log{Address: common.Address{0x01}, Topics: []common.Hash{{0xaa}}, Data: []byte{0x01}}
+	b := &types.Log{Address: common.Address{0x01}, Topics: []common.Hash{{0xbb}}}
+	callback := &types.Log{Address: common.Address{0x01}, Topics: []common.Hash{{0xcc}}}
+	copyLog := func(log *types.Log) *types.Log {
+		cpy := *log
+		return &cpy
+	}
+
+	onchainA, onchainB := copyLog(a), copyLog(b)
+	reemitted, rest := matchOffchainLogs([]*types.Log{onchainA, onchainB, callback}, []*types.Log{a, b})
+	require.Equal(t, map[*types.Log]bool{onchainA: true, onchainB: true}, reemitted)
+	require.Empty(t, rest)
+
+	reemitted, rest = matchOffchainLogs([]*types.Log{callback}, []*types.Log{a, b})
+	require.Empty(t, reemitted)
+	require.Equal(t, []*types.Log{a, b}, rest)
+
+	// an on-chain log is only matched once
+	onchainA = copyLog(a)
+	reemitted, rest = matchOffchainLogs([]*types.Log{onchainA, callback}, []*types.Log{a, copyLog(a)})
+	require.Equal(t, map[*types.Log]bool{onchainA: true}, reemitted)
+	require.Len(t, rest, 1)
+}
@@ -0,0 +1,142 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// OfflineRequest are the fields of a confidential request signed with
+// SignConfidentialRequest. Since no node is queried, all of them are explicit.
+type OfflineRequest struct {
+	ChainID       *big.Int
+	KettleAddress common.Address
+
+	Nonce    uint64
+	GasPrice *big.Int
+
+	// Gas is the gas limit, defaultGasLimit if zero.
+	Gas uint64
+
+	To    common.Address
+	Value *big.Int
+
+	// Data is the calldata of the confidential method, i.e. Abi.Pack(method, args...).
+	Data []byte
+
+	ConfidentialInputs []byte
+
+	// EIP712 signs the request with an EIP-712 envelope like the framework does
+	// with the SUAVE chain, instead of the legacy transaction hash. A RemoteSigner
+	// always uses the envelope.
+	EIP712 bool
+}
+
+// SignConfidentialRequest signs the confidential request with acct without
+// accessing the chain and returns its binary encoding, ready for Chain.Broadcast.
+func SignConfidentialRequest(ctx context.Context, acct Signer, req *OfflineRequest) ([]byte, error) {
+	if req.ChainID == nil || req.GasPrice == nil {
+		return nil, fmt.Errorf("offline request without chain id or gas price")
+	}
+
+	record := types.ConfidentialComputeRecord{
+		KettleAddress: req.KettleAddress,
+		Nonce:         req.Nonce,
+		To:            &req.To,
+		GasPrice:      req.GasPrice,
+		Gas:           req.Gas,
+		Value:         req.Value,
+		Data:          req.Data,
+	}
+	if record.Gas == 0 {
+		record.Gas = defaultGasLimit
+	}
+	if req.EIP712 {
+		record.ChainID = req.ChainID
+		record.IsEIP712 = true
+	}
+
+	signed, err := signConfidentialRequest(ctx, acct, req.ChainID, record, req.ConfidentialInputs)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+// signConfidentialRequest signs the confidential request of the record with acct.
+func signConfidentialRequest(ctx context.Context, acct Signer, chainID *big.Int, record types.ConfidentialComputeRecord, confidentialInputs []byte) (*types.Transaction, error) {
+	record.ConfidentialInputsHash = crypto.Keccak256Hash(confidentialInputs)
+	return acct.SignTx(ctx, types.NewTx(&types.ConfidentialComputeRequest{
+		ConfidentialComputeRecord: record,
+		ConfidentialInputs:        confidentialInputs,
+	}), chainID)
+}
+
+// WriteRawTransaction writes a signed transaction or confidential request to
+// path, hex encoded.
+func WriteRawTransaction(path string, raw []byte) error {
+	return os.WriteFile(path, []byte(hexutil.Encode(raw)+"\n"), 0o600)
+}
+
+// ReadRawTransaction reads a transaction written with WriteRawTransaction.
+func ReadRawTransaction(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hexutil.Decode(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction in %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+// Broadcast submits a signed transaction or confidential request and waits for
+// its receipt. For confidential requests, it is the receipt of the callback.
+func (c *Chain) Broadcast(raw []byte) *types.Receipt {
+	ctx, cancel := c.defaultContext()
+	defer cancel()
+
+	receipt, err := c.BroadcastE(ctx, raw)
+	if err != nil {
+		panic(err)
+	}
+	return receipt
+}
+
+// BroadcastE is like Broadcast but returns an error instead of panicking.
+func (c *Chain) BroadcastE(ctx context.Context, raw []byte) (*types.Receipt, error) {
+	txn := new(types.Transaction)
+	if err := txn.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid raw transaction: %w", err)
+	}
+
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	hash, err := c.sendRawTransaction(ctx, txn)
+	if err != nil {
+		if revertErr := NewRevertDecoder().DecodeError(err); revertErr != nil {
+			return nil, revertErr
+		}
+		return nil, err
+	}
+
+	c.logTx(hash)
+
+	receipt, err := c.WaitForReceipt(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return nil, &TxError{Hash: hash, Receipt: receipt}
+	}
+	return receipt, nil
+}
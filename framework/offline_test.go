@@ -0,0 +1,180 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignConfidentialRequest(t *testing.T) {
+	chainID := big.NewInt(16813125)
+	acct := GeneratePrivKey()
+	kettle := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	cases := []struct {
+		name    string
+		req     OfflineRequest
+		wantGas uint64
+		wantErr bool
+	}{
+		{
+			name:    "legacy",
+			req:     OfflineRequest{ChainID: chainID, KettleAddress: kettle, Nonce: 3, GasPrice: big.NewInt(10), Gas: 50000, To: to, Data: []byte{0x01}, ConfidentialInputs: []byte{0x02}},
+			wantGas: 50000,
+		},
+		{
+			name:    "EIP-712",
+			req:     OfflineRequest{ChainID: chainID, KettleAddress: kettle, Nonce: 3, GasPrice: big.NewInt(10), Gas: 50000, To: to, Data: []byte{0x01}, ConfidentialInputs: []byte{0x02}, EIP712: true},
+			wantGas: 50000,
+		},
+		{
+			name:    "default gas",
+			req:     OfflineRequest{ChainID: chainID, KettleAddress: kettle, GasPrice: big.NewInt(10), To: to, Value: big.NewInt(7)},
+			wantGas: defaultGasLimit,
+		},
+		{
+			name:    "no chain id",
+			req:     OfflineRequest{KettleAddress: kettle, GasPrice: big.NewInt(10), To: to},
+			wantErr: true,
+		},
+		{
+			name:    "no gas price",
+			req:     OfflineRequest{ChainID: chainID, KettleAddress: kettle, To: to},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := SignConfidentialRequest(context.Background(), acct, &tc.req)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			signed := new(types.Transaction)
+			require.NoError(t, signed.UnmarshalBinary(raw))
+
+			// the request survives a round trip through a file
+			path := filepath.Join(t.TempDir(), "request.hex")
+			require.NoError(t, WriteRawTransaction(path, raw))
+			read, err := ReadRawTransaction(path)
+			require.NoError(t, err)
+			require.Equal(t, raw, read)
+
+			txn := new(types.Transaction)
+			require.NoError(t, txn.UnmarshalBinary(read))
+			require.Equal(t, signed.Hash(), txn.Hash())
+
+			sender, err := types.Sender(types.NewSuaveSigner(chainID), txn)
+			require.NoError(t, err)
+			require.Equal(t, acct.Address(), sender)
+
+			ccr, ok := types.CastTxInner[*types.ConfidentialComputeRequest](txn)
+			require.True(t, ok)
+			require.Equal(t, tc.req.EIP712, ccr.IsEIP712)
+			require.Equal(t, tc.req.KettleAddress, ccr.KettleAddress)
+			require.Equal(t, tc.req.Nonce, ccr.Nonce)
+			require.Equal(t, tc.wantGas, ccr.Gas)
+			require.True(t, bytes.Equal(tc.req.ConfidentialInputs, ccr.ConfidentialInputs))
+			require.Equal(t, crypto.Keccak256Hash(tc.req.ConfidentialInputs), ccr.ConfidentialInputsHash)
+		})
+	}
+}
+
+func TestReadRawTransaction(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    []byte
+		wantErr bool
+	}{
+		{name: "hex", content: "0x0102\n", want: []byte{0x01, 0x02}},
+		{name: "surrounding whitespace", content: "  0x0102 \n\n", want: []byte{0x01, 0x02}},
+		{name: "no prefix", content: "0102", wantErr: true},
+		{name: "odd length", content: "0x012", wantErr: true},
+		{name: "not hex", content: "0xzz", wantErr: true},
+		{name: "binary", content: "\x01\x02", wantErr: true},
+		{name: "empty", content: "", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tx.hex")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			got, err := ReadRawTransaction(path)
+			if tc.wantErr {
+				require.ErrorContains(t, err, "invalid raw transaction")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := ReadRawTransaction(filepath.Join(t.TempDir(), "missing.hex"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestBroadcast(t *testing.T) {
+	contract, node := newTestRequestContract(t)
+	acct := GeneratePrivKey()
+
+	sign := func(t *testing.T, nonce uint64, inputs []byte) []byte {
+		t.Helper()
+
+		calldata, err := contract.Abi.Pack("submit", uint64(1))
+		require.NoError(t, err)
+		raw, err := SignConfidentialRequest(context.Background(), acct, &OfflineRequest{
+			ChainID:            node.chainID,
+			KettleAddress:      node.kettle.Address(),
+			Nonce:              nonce,
+			GasPrice:           big.NewInt(1),
+			To:                 contract.addr,
+			Data:               calldata,
+			ConfidentialInputs: inputs,
+			EIP712:             true,
+		})
+		require.NoError(t, err)
+		return raw
+	}
+
+	cases := []struct {
+		name    string
+		raw     []byte
+		wantErr string
+		wantIs  error
+	}{
+		{name: "included", raw: sign(t, 0, nil)},
+		{name: "failed", raw: sign(t, 1, []byte("fail")), wantIs: ErrTransactionFailed},
+		{name: "rejected", raw: sign(t, 2, []byte("reject")), wantErr: "confidential request rejected"},
+		{name: "malformed", raw: []byte{0x01, 0x02}, wantErr: "invalid raw transaction"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			receipt, err := contract.chain.BroadcastE(context.Background(), tc.raw)
+			if tc.wantIs != nil {
+				require.ErrorIs(t, err, tc.wantIs)
+				return
+			}
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+		})
+	}
+}
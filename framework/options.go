@@ -0,0 +1,118 @@
+package framework
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// txConfig are the fields of a transaction or confidential request set with
+// the TxOptions. The fields left unset are filled from the chain.
+type txConfig struct {
+	gas      uint64
+	gasPrice *big.Int
+	value    *big.Int
+	nonce    *uint64
+}
+
+// TxOption sets a field of the transaction of DeployContract or of a
+// confidential request. It is both a DeployOption and a RequestOption.
+type TxOption func(c *txConfig)
+
+func (o TxOption) applyDeploy(c *deployConfig) { o(&c.txConfig) }
+
+func (o TxOption) applyRequest(c *requestConfig) { o(&c.txConfig) }
+
+// WithGasLimit sets the gas limit instead of estimating it.
+func WithGasLimit(gas uint64) TxOption {
+	return func(c *txConfig) {
+		c.gas = gas
+	}
+}
+
+// WithGasPrice sets the gas price instead of using the one suggested by the node.
+func WithGasPrice(gasPrice *big.Int) TxOption {
+	return func(c *txConfig) {
+		c.gasPrice = gasPrice
+	}
+}
+
+// WithValue sets the value sent to a payable constructor or method.
+func WithValue(value *big.Int) TxOption {
+	return func(c *txConfig) {
+		c.value = value
+	}
+}
+
+// WithNonce sets the nonce instead of allocating the next one of the account.
+func WithNonce(nonce uint64) TxOption {
+	return func(c *txConfig) {
+		c.nonce = &nonce
+	}
+}
+
+type requestConfig struct {
+	txConfig
+	kettle *common.Address
+	noSend bool
+}
+
+// RequestOption configures a confidential request. The TxOptions are also RequestOptions.
+type RequestOption interface {
+	applyRequest(c *requestConfig)
+}
+
+type requestOption func(c *requestConfig)
+
+func (o requestOption) applyRequest(c *requestConfig) { o(c) }
+
+// WithKettle sends the request to the given kettle instead of selecting it with the
+// kettle policy, without failover. A kettle that is not one of the configured kettles
+// is reached through the endpoint of the chain.
+func WithKettle(kettle common.Address) RequestOption {
+	return requestOption(func(c *requestConfig) {
+		c.kettle = &kettle
+	})
+}
+
+// WithNoSend signs the request without sending it, see PendingRequest.Request.
+// The nonce of the request is allocated as if it was sent, call Chain.ResetNonce
+// if the request is discarded.
+func WithNoSend() RequestOption {
+	return requestOption(func(c *requestConfig) {
+		c.noSend = true
+	})
+}
+
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	cfg := &requestConfig{}
+	for _, opt := range opts {
+		opt.applyRequest(cfg)
+	}
+	return cfg
+}
+
+type eventConfig struct {
+	offchain bool
+}
+
+// EventOption configures the decoding of the events of a receipt.
+type EventOption func(c *eventConfig)
+
+// WithOffchainEvents also decodes the events emitted off-chain by the confidential
+// request of the receipt, see Chain.OffchainLogs. It fetches the transaction of
+// the receipt. The events that the callback re-emits with emitOffchainLogs are
+// only returned once, as on-chain events with Offchain set.
+func WithOffchainEvents() EventOption {
+	return func(c *eventConfig) {
+		c.offchain = true
+	}
+}
+
+func newEventConfig(opts []EventOption) *eventConfig {
+	cfg := &eventConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
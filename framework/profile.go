@@ -0,0 +1,195 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sethvargo/go-envconfig"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ProfileEnv is the environment variable with the name of the profile to use.
+	ProfileEnv = "SUAPP_PROFILE"
+
+	// ProfilesFileEnv is the environment variable with the path of the profiles file.
+	ProfilesFileEnv = "SUAPP_PROFILES"
+)
+
+// defaultProfilesFiles are the names of the profiles file looked up in the
+// working directory and in the root of this repository.
+var defaultProfilesFiles = []string{"suapp.yaml", "suapp.yml", "suapp.toml"}
+
+// Profile is a named network configuration of a profiles file, i.e. 'local',
+// 'kurtosis' or 'testnet'. The fields left empty keep their default values, and
+// all of them are overridden by their environment variables.
+type Profile struct {
+	Suave ProfileChain `yaml:"suave" toml:"suave"`
+	L1    ProfileChain `yaml:"l1" toml:"l1"`
+
+	Mnemonic       string `yaml:"mnemonic" toml:"mnemonic"`
+	DerivationPath string `yaml:"derivation_path" toml:"derivation_path"`
+	ArtifactsDir   string `yaml:"artifacts_dir" toml:"artifacts_dir"`
+}
+
+// ProfileChain is the configuration of a chain in a Profile.
+type ProfileChain struct {
+	RPC     string `yaml:"rpc" toml:"rpc"`
+	ChainID uint64 `yaml:"chain_id" toml:"chain_id"`
+
+	// Kettles are the kettle endpoints and KettleAddresses the kettles to use
+	// among the ones they report. Only used for the SUAVE chain.
+	Kettles         []string `yaml:"kettles" toml:"kettles"`
+	KettleAddresses []string `yaml:"kettle_addresses" toml:"kettle_addresses"`
+
+	Explorer string `yaml:"explorer" toml:"explorer"`
+
+	PrivKey              string `yaml:"privkey" toml:"privkey"`
+	Keystore             string `yaml:"keystore" toml:"keystore"`
+	KeystorePasswordFile string `yaml:"keystore_password_file" toml:"keystore_password_file"`
+	KeystoreAccount      string `yaml:"keystore_account" toml:"keystore_account"`
+}
+
+// LoadProfile reads the profile name from the profiles file at path, a YAML or
+// TOML file mapping the profile names to their Profile. If path is empty, it uses
+// SUAPP_PROFILES or looks up suapp.yaml, suapp.yml or suapp.toml.
+func LoadProfile(path, name string) (*Profile, error) {
+	if path == "" {
+		path = defaultProfilesFile()
+	}
+	if path == "" {
+		return nil, fmt.Errorf("profile '%s': no profiles file found", name)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var profiles map[string]*Profile
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &profiles)
+	case ".toml":
+		err = toml.Unmarshal(data, &profiles)
+	default:
+		return nil, fmt.Errorf("profiles file %s: unknown format '%s'", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("profiles file %s: %w", path, err)
+	}
+
+	profile, ok := profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("profile '%s' not found in %s", name, path)
+	}
+	return profile, nil
+}
+
+func defaultProfilesFile() string {
+	if path := os.Getenv(ProfilesFileEnv); path != "" {
+		return path
+	}
+
+	dirs := []string{"."}
+	// the root of this repository, when running from it
+	if _, filename, _, ok := runtime.Caller(0); ok {
+		dirs = append(dirs, filepath.Join(filepath.Dir(filename), ".."))
+	}
+	for _, dir := range dirs {
+		for _, name := range defaultProfilesFiles {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// env returns the profile as the environment variables of its Config fields.
+func (p *Profile) env() map[string]string {
+	env := map[string]string{}
+	set := func(key, value string) {
+		if value != "" {
+			env[key] = value
+		}
+	}
+	setChainID := func(key string, id uint64) {
+		if id != 0 {
+			env[key] = strconv.FormatUint(id, 10)
+		}
+	}
+
+	set("KETTLE_RPC", p.Suave.RPC)
+	set("KETTLE_RPCS", strings.Join(p.Suave.Kettles, ","))
+	set("KETTLE_ADDRESSES", strings.Join(p.Suave.KettleAddresses, ","))
+	setChainID("SUAVE_CHAIN_ID", p.Suave.ChainID)
+	set("SUAVE_EXPLORER_URL", p.Suave.Explorer)
+	set("KETTLE_PRIVKEY", p.Suave.PrivKey)
+	set("KETTLE_KEYSTORE", p.Suave.Keystore)
+	set("KETTLE_KEYSTORE_PASSWORD_FILE", p.Suave.KeystorePasswordFile)
+	set("KETTLE_KEYSTORE_ACCOUNT", p.Suave.KeystoreAccount)
+
+	set("L1_RPC", p.L1.RPC)
+	setChainID("L1_CHAIN_ID", p.L1.ChainID)
+	set("L1_EXPLORER_URL", p.L1.Explorer)
+	set("L1_PRIVKEY", p.L1.PrivKey)
+	set("L1_KEYSTORE", p.L1.Keystore)
+	set("L1_KEYSTORE_PASSWORD_FILE", p.L1.KeystorePasswordFile)
+	set("L1_KEYSTORE_ACCOUNT", p.L1.KeystoreAccount)
+
+	set("MNEMONIC", p.Mnemonic)
+	set("DERIVATION_PATH", p.DerivationPath)
+	set("ARTIFACTS_DIR", p.ArtifactsDir)
+	return env
+}
+
+// setEnvLookuper looks up the environment variables that are set and not empty,
+// so that the empty variables of a .env file do not override the profile.
+type setEnvLookuper struct{}
+
+func (setEnvLookuper) Lookup(key string) (string, bool) {
+	value := os.Getenv(key)
+	return value, value != ""
+}
+
+// LoadConfig loads the framework configuration like New: the profile selected with
+// WithProfile or SUAPP_PROFILE, if any, overridden by the environment variables and
+// then by the options.
+func LoadConfig(ctx context.Context, opts ...ConfigOption) (*Config, error) {
+	// the options are applied a first time to find the profile
+	var selected Config
+	for _, opt := range opts {
+		opt(&selected)
+	}
+	name := selected.Profile
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+
+	lookuper := envconfig.OsLookuper()
+	if name != "" {
+		profile, err := LoadProfile(selected.ProfilesFile, name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+		lookuper = envconfig.MultiLookuper(setEnvLookuper{}, envconfig.MapLookuper(profile.env()))
+	}
+
+	var config Config
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{Target: &config, Lookuper: lookuper}); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+	config.Profile = name
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &config, nil
+}
@@ -0,0 +1,224 @@
+package framework
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testProfilesYAML = `
+devnet:
+  suave:
+    rpc: http://suave:8545
+    chain_id: 16813125
+    kettles: [http://kettle-1:8545, http://kettle-2:8545]
+  l1:
+    rpc: http://l1:8545
+    chain_id: 1337
+  mnemonic: test test test test test test test test test test test junk
+other:
+  suave:
+    rpc: http://other:8545
+`
+
+const testProfilesTOML = `
+[devnet.suave]
+rpc = "http://suave:8545"
+chain_id = 16813125
+
+[devnet.l1]
+rpc = "http://l1:8545"
+`
+
+// clearConfigEnv unsets the environment variables of the configuration for
+// the duration of the test.
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+
+	for _, key := range []string{
+		ProfileEnv, ProfilesFileEnv,
+		"KETTLE_RPC", "KETTLE_RPCS", "KETTLE_ADDRESSES", "SUAVE_CHAIN_ID", "SUAVE_EXPLORER_URL",
+		"KETTLE_PRIVKEY", "KETTLE_KEYSTORE", "KETTLE_KEYSTORE_PASSWORD_FILE", "KETTLE_KEYSTORE_ACCOUNT",
+		"L1_RPC", "L1_CHAIN_ID", "L1_EXPLORER_URL", "L1_PRIVKEY",
+		"L1_KEYSTORE", "L1_KEYSTORE_PASSWORD_FILE", "L1_KEYSTORE_ACCOUNT",
+		"MNEMONIC", "DERIVATION_PATH", "ARTIFACTS_DIR", "VERIFY_RESULTS",
+		"RECEIPT_TIMEOUT_BLOCKS", "REQUEST_TIMEOUT",
+	} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func writeTestFile(t *testing.T, name, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+func TestLoadProfile(t *testing.T) {
+	yamlFile := writeTestFile(t, "suapp.yaml", testProfilesYAML)
+	tomlFile := writeTestFile(t, "suapp.toml", testProfilesTOML)
+
+	cases := []struct {
+		name      string
+		path      string
+		profile   string
+		wantRPC   string
+		wantChain uint64
+		wantErr   bool
+	}{
+		{name: "yaml", path: yamlFile, profile: "devnet", wantRPC: "http://suave:8545", wantChain: 16813125},
+		{name: "yaml other profile", path: yamlFile, profile: "other", wantRPC: "http://other:8545"},
+		{name: "toml", path: tomlFile, profile: "devnet", wantRPC: "http://suave:8545", wantChain: 16813125},
+		{name: "unknown profile", path: yamlFile, profile: "mainnet", wantErr: true},
+		{name: "missing file", path: filepath.Join(t.TempDir(), "suapp.yaml"), profile: "devnet", wantErr: true},
+		{name: "unknown format", path: writeTestFile(t, "suapp.json", `{}`), profile: "devnet", wantErr: true},
+		{name: "invalid yaml", path: writeTestFile(t, "suapp.yml", "devnet: ["), profile: "devnet", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile, err := LoadProfile(tc.path, tc.profile)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantRPC, profile.Suave.RPC)
+			require.Equal(t, tc.wantChain, profile.Suave.ChainID)
+		})
+	}
+}
+
+func TestLoadProfileFromEnv(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv(ProfilesFileEnv, writeTestFile(t, "profiles.yaml", testProfilesYAML))
+
+	profile, err := LoadProfile("", "other")
+	require.NoError(t, err)
+	require.Equal(t, "http://other:8545", profile.Suave.RPC)
+}
+
+func TestLoadConfig(t *testing.T) {
+	profiles := writeTestFile(t, "suapp.yaml", testProfilesYAML)
+
+	cases := []struct {
+		name string
+		env  map[string]string
+		opts []ConfigOption
+		// check is called with the loaded configuration
+		check   func(t *testing.T, config *Config)
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, config *Config) {
+				require.Empty(t, config.Profile)
+				require.Equal(t, "http://localhost:8545", config.KettleRPC)
+				require.Equal(t, "http://localhost:8555", config.L1RPC)
+				require.Equal(t, uint64(10), config.ReceiptTimeoutBlocks)
+				require.False(t, config.VerifyResults)
+			},
+		},
+		{
+			name: "environment",
+			env:  map[string]string{"KETTLE_RPC": "http://env:8545", "VERIFY_RESULTS": "true"},
+			check: func(t *testing.T, config *Config) {
+				require.Equal(t, "http://env:8545", config.KettleRPC)
+				require.True(t, config.VerifyResults)
+			},
+		},
+		{
+			name: "profile",
+			opts: []ConfigOption{WithProfile("devnet")},
+			check: func(t *testing.T, config *Config) {
+				require.Equal(t, "devnet", config.Profile)
+				require.Equal(t, "http://suave:8545", config.KettleRPC)
+				require.Equal(t, []string{"http://kettle-1:8545", "http://kettle-2:8545"}, config.KettleRPCs)
+				require.Equal(t, uint64(16813125), config.SuaveChainID)
+				require.Equal(t, "http://l1:8545", config.L1RPC)
+				require.Equal(t, uint64(1337), config.L1ChainID)
+				require.Equal(t, DefaultMnemonic, config.Mnemonic)
+				// the fields that are not in the profile keep their default
+				require.Equal(t, uint64(10), config.ReceiptTimeoutBlocks)
+			},
+		},
+		{
+			name: "profile selected with the environment",
+			env:  map[string]string{ProfileEnv: "other"},
+			check: func(t *testing.T, config *Config) {
+				require.Equal(t, "other", config.Profile)
+				require.Equal(t, "http://other:8545", config.KettleRPC)
+			},
+		},
+		{
+			name: "option selects the profile over the environment",
+			env:  map[string]string{ProfileEnv: "other"},
+			opts: []ConfigOption{WithProfile("devnet")},
+			check: func(t *testing.T, config *Config) {
+				require.Equal(t, "devnet", config.Profile)
+				require.Equal(t, "http://suave:8545", config.KettleRPC)
+			},
+		},
+		{
+			name: "environment overrides the profile",
+			env:  map[string]string{"KETTLE_RPC": "http://env:8545", "L1_CHAIN_ID": "31337"},
+			opts: []ConfigOption{WithProfile("devnet")},
+			check: func(t *testing.T, config *Config) {
+				require.Equal(t, "http://env:8545", config.KettleRPC)
+				require.Equal(t, uint64(31337), config.L1ChainID)
+				require.Equal(t, "http://l1:8545", config.L1RPC)
+			},
+		},
+		{
+			name: "empty environment does not override the profile",
+			env:  map[string]string{"KETTLE_RPC": "", "L1_RPC": ""},
+			opts: []ConfigOption{WithProfile("devnet")},
+			check: func(t *testing.T, config *Config) {
+				require.Equal(t, "http://suave:8545", config.KettleRPC)
+				require.Equal(t, "http://l1:8545", config.L1RPC)
+			},
+		},
+		{
+			name: "options override the environment",
+			env:  map[string]string{"VERIFY_RESULTS": "false"},
+			opts: []ConfigOption{WithProfile("devnet"), WithResultVerification()},
+			check: func(t *testing.T, config *Config) {
+				require.True(t, config.VerifyResults)
+			},
+		},
+		{
+			name:    "unknown profile",
+			opts:    []ConfigOption{WithProfile("mainnet")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid environment",
+			env:     map[string]string{"SUAVE_CHAIN_ID": "suave"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			opts := append([]ConfigOption{WithProfilesFile(profiles)}, tc.opts...)
+			config, err := LoadConfig(context.Background(), opts...)
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrConfig)
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, config)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// RemoteSigner signs through the JSON-RPC API of an external signer like clef.
+//
+// Regular transactions are signed with account_signTransaction and confidential
+// requests with account_signTypedData, both part of the clef API. Confidential
+// requests are always signed with their EIP-712 envelope, the legacy ones are
+// converted. Raw hashes use account_signHash, which is not in clef and only
+// available in signers that implement it, like the one of NewSignerServer.
+// Blob transactions cannot be signed.
+type RemoteSigner struct {
+	rpc  *rpc.Client
+	addr common.Address
+}
+
+var _ Signer = &RemoteSigner{}
+
+// NewRemoteSigner returns a signer for the account addr of the remote signer.
+func NewRemoteSigner(client *rpc.Client, addr common.Address) *RemoteSigner {
+	return &RemoteSigner{rpc: client, addr: addr}
+}
+
+// DialRemoteSigner connects to the remote signer at url. If addr is the zero
+// address, it uses the first account of the signer.
+func DialRemoteSigner(ctx context.Context, url string, addr common.Address) (*RemoteSigner, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, &DialError{URL: url, Err: err}
+	}
+	if addr == (common.Address{}) {
+		var accounts []common.Address
+		if err := client.CallContext(ctx, &accounts, "account_list"); err != nil {
+			return nil, fmt.Errorf("failed to list the signer accounts: %w", err)
+		}
+		if len(accounts) == 0 {
+			return nil, fmt.Errorf("signer at %s has no accounts", url)
+		}
+		addr = accounts[0]
+	}
+	return NewRemoteSigner(client, addr), nil
+}
+
+func (r *RemoteSigner) Address() common.Address {
+	return r.addr
+}
+
+func (r *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.NewSuaveSigner(chainID)
+
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType:
+		var res signTransactionResult
+		if err := r.rpc.CallContext(ctx, &res, "account_signTransaction", newSendTxArgs(r.addr, tx, chainID)); err != nil {
+			return nil, err
+		}
+		signed := new(types.Transaction)
+		if err := signed.UnmarshalBinary(res.Raw); err != nil {
+			return nil, fmt.Errorf("invalid signed transaction: %w", err)
+		}
+		if signer.Hash(signed) != signer.Hash(tx) {
+			return nil, fmt.Errorf("signer modified the transaction")
+		}
+		if sender, err := types.Sender(signer, signed); err != nil || sender != r.addr {
+			return nil, fmt.Errorf("transaction not signed by %s", r.addr.Hex())
+		}
+		return signed, nil
+
+	case types.ConfidentialComputeRequestTxType:
+		request, _ := types.CastTxInner[*types.ConfidentialComputeRequest](tx)
+		if !request.IsEIP712 {
+			// clef cannot sign the hash of a legacy confidential request
+			record := request.ConfidentialComputeRecord
+			record.ChainID = chainID
+			record.IsEIP712 = true
+			tx = types.NewTx(&types.ConfidentialComputeRequest{
+				ConfidentialComputeRecord: record,
+				ConfidentialInputs:        request.ConfidentialInputs,
+			})
+			request, _ = types.CastTxInner[*types.ConfidentialComputeRequest](tx)
+		}
+		sig, err := r.SignTypedData(ctx, confidentialRecordTypedData(&request.ConfidentialComputeRecord))
+		if err != nil {
+			return nil, err
+		}
+		return tx.WithSignature(signer, sig)
+	}
+	return nil, fmt.Errorf("%w: transaction type %d", ErrUnsupportedSigner, tx.Type())
+}
+
+func (r *RemoteSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := r.rpc.CallContext(ctx, &sig, "account_signTypedData", common.NewMixedcaseAddress(r.addr), data); err != nil {
+		return nil, err
+	}
+	return normalizeSignature(sig)
+}
+
+func (r *RemoteSigner) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := r.rpc.CallContext(ctx, &sig, "account_signHash", common.NewMixedcaseAddress(r.addr), hash); err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+			return nil, fmt.Errorf("%w: account_signHash is not available", ErrUnsupportedSigner)
+		}
+		return nil, err
+	}
+	return normalizeSignature(sig)
+}
+
+// methodNotFoundCode is the JSON-RPC error code of an unknown method.
+const methodNotFoundCode = -32601
+
+// normalizeSignature converts the V of clef signatures from 27/28 to 0/1.
+func normalizeSignature(sig []byte) ([]byte, error) {
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	sig = common.CopyBytes(sig)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	return sig, nil
+}
+
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func newSendTxArgs(from common.Address, tx *types.Transaction, chainID *big.Int) apitypes.SendTxArgs {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(from),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if to := tx.To(); to != nil {
+		mixed := common.NewMixedcaseAddress(*to)
+		args.To = &mixed
+	}
+	switch tx.Type() {
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	case types.AccessListTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+	return args
+}
+
+// NewSignerServer returns a JSON-RPC server with the API of RemoteSigner that
+// signs with the given signers, i.e. to stand in for an external signer in tests.
+func NewSignerServer(signers ...Signer) (*rpc.Server, error) {
+	api := &signerAPI{signers: map[common.Address]Signer{}}
+	for _, s := range signers {
+		api.signers[s.Address()] = s
+		api.accounts = append(api.accounts, s.Address())
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", api); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+type signerAPI struct {
+	signers  map[common.Address]Signer
+	accounts []common.Address
+}
+
+func (a *signerAPI) signer(addr common.MixedcaseAddress) (Signer, error) {
+	s, ok := a.signers[addr.Address()]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s", addr.Address().Hex())
+	}
+	return s, nil
+}
+
+func (a *signerAPI) List(ctx context.Context) ([]common.Address, error) {
+	return a.accounts, nil
+}
+
+func (a *signerAPI) SignTransaction(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*signTransactionResult, error) {
+	s, err := a.signer(args.From)
+	if err != nil {
+		return nil, err
+	}
+	if args.ChainID == nil {
+		return nil, fmt.Errorf("missing chain id")
+	}
+	signed, err := s.SignTx(ctx, args.ToTransaction(), args.ChainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw, Tx: signed}, nil
+}
+
+func (a *signerAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, data apitypes.TypedData) (hexutil.Bytes, error) {
+	s, err := a.signer(addr)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.SignTypedData(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27 // like clef
+	return sig, nil
+}
+
+func (a *signerAPI) SignHash(ctx context.Context, addr common.MixedcaseAddress, hash common.Hash) (hexutil.Bytes, error) {
+	s, err := a.signer(addr)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.SignHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
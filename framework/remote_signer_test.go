@@ -0,0 +1,142 @@
+package framework
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/require"
+)
+
+// testClefAPI has the methods of the clef API, i.e. no account_signHash.
+type testClefAPI struct {
+	api *signerAPI
+}
+
+func (c *testClefAPI) List(ctx context.Context) ([]common.Address, error) {
+	return c.api.List(ctx)
+}
+
+func (c *testClefAPI) SignTransaction(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*signTransactionResult, error) {
+	return c.api.SignTransaction(ctx, args, methodSelector)
+}
+
+func (c *testClefAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, data apitypes.TypedData) (hexutil.Bytes, error) {
+	return c.api.SignTypedData(ctx, addr, data)
+}
+
+func newTestRemoteSigner(t *testing.T, acct *PrivKey, clef bool) *RemoteSigner {
+	t.Helper()
+
+	server, err := NewSignerServer(acct)
+	require.NoError(t, err)
+	if clef {
+		server = rpc.NewServer()
+		api := &signerAPI{signers: map[common.Address]Signer{acct.Address(): acct}}
+		require.NoError(t, server.RegisterName("account", &testClefAPI{api: api}))
+	}
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+	return NewRemoteSigner(client, acct.Address())
+}
+
+func TestConfidentialRecordTypedData(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	record := &types.ConfidentialComputeRecord{
+		Nonce:                  3,
+		To:                     &to,
+		Gas:                    1_000_000,
+		GasPrice:               new(big.Int).Lsh(big.NewInt(1), 70),
+		Value:                  big.NewInt(5),
+		Data:                   []byte{0x01, 0x02, 0x03},
+		KettleAddress:          common.HexToAddress("0x00000000000000000000000000000000000000b1"),
+		ConfidentialInputsHash: crypto.Keccak256Hash([]byte("inputs")),
+		ChainID:                big.NewInt(16813125),
+		IsEIP712:               true,
+	}
+
+	want, err := record.EIP712Hash()
+	require.NoError(t, err)
+	got, _, err := apitypes.TypedDataAndHash(confidentialRecordTypedData(record))
+	require.NoError(t, err)
+	require.Equal(t, want.Bytes(), got)
+}
+
+func TestRemoteSignerSignTx(t *testing.T) {
+	acct := GeneratePrivKey()
+	chainID := big.NewInt(16813125)
+	signer := types.NewSuaveSigner(chainID)
+	to := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	record := types.ConfidentialComputeRecord{
+		To:                     &to,
+		Gas:                    1_000_000,
+		GasPrice:               big.NewInt(1),
+		KettleAddress:          common.HexToAddress("0x00000000000000000000000000000000000000b1"),
+		ConfidentialInputsHash: crypto.Keccak256Hash([]byte{0x01}),
+	}
+
+	cases := []struct {
+		name    string
+		tx      *types.Transaction
+		wantErr error
+	}{
+		{
+			name: "dynamic fee",
+			tx:   types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, To: &to, Gas: 21000, GasFeeCap: big.NewInt(2), GasTipCap: big.NewInt(1), Value: big.NewInt(1)}),
+		},
+		{
+			name: "legacy",
+			tx:   types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Gas: 21000, GasPrice: big.NewInt(1)}),
+		},
+		{
+			name: "legacy confidential request",
+			tx:   types.NewTx(&types.ConfidentialComputeRequest{ConfidentialComputeRecord: record, ConfidentialInputs: []byte{0x01}}),
+		},
+		{
+			name:    "blob",
+			tx:      types.NewTx(&types.BlobTx{Gas: 21000}),
+			wantErr: ErrUnsupportedSigner,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			remote := newTestRemoteSigner(t, acct, true)
+			signed, err := remote.SignTx(context.Background(), tc.tx, chainID)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			sender, err := types.Sender(signer, signed)
+			require.NoError(t, err)
+			require.Equal(t, acct.Address(), sender)
+
+			if request, ok := types.CastTxInner[*types.ConfidentialComputeRequest](signed); ok {
+				require.True(t, request.IsEIP712, "clef can only sign the EIP-712 envelope")
+				require.Equal(t, tc.tx.Data(), signed.Data())
+			}
+		})
+	}
+}
+
+func TestRemoteSignerSignHash(t *testing.T) {
+	acct := GeneratePrivKey()
+	hash := crypto.Keccak256Hash([]byte("hash"))
+
+	sig, err := newTestRemoteSigner(t, acct, false).SignHash(context.Background(), hash)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(hash[:], sig)
+	require.NoError(t, err)
+	require.Equal(t, acct.Address(), crypto.PubkeyToAddress(*pub))
+
+	_, err = newTestRemoteSigner(t, acct, true).SignHash(context.Background(), hash)
+	require.ErrorIs(t, err, ErrUnsupportedSigner)
+}
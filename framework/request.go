@@ -0,0 +1,163 @@
+package framework
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PendingRequest is a confidential request accepted by the kettle whose
+// callback transaction may not be included yet.
+type PendingRequest struct {
+	// Hash is the hash of the callback transaction, zero if the request was not sent.
+	Hash common.Hash
+
+	// Request is the signed confidential request.
+	Request *types.Transaction
+
+	contract *Contract
+	method   string
+
+	mu      sync.Mutex
+	receipt *types.Receipt
+	err     error
+}
+
+// Wait waits until the callback transaction is included and returns its receipt.
+// Like SendConfidentialRequestE, it returns a *CallError if the callback failed,
+// or if the result does not verify when the framework is configured to verify them.
+// Once it returns a receipt or a callback error, later calls return the same result.
+// Wait can be called concurrently, Receipt does not block while it waits.
+func (p *PendingRequest) Wait(ctx context.Context) (*types.Receipt, error) {
+	if receipt, err := p.result(); receipt != nil {
+		return receipt, err
+	}
+	if p.Hash == (common.Hash{}) {
+		return nil, &CallError{Method: p.method, Err: ErrNotSent}
+	}
+
+	receipt, err := p.contract.chain.WaitForReceipt(ctx, p.Hash)
+	if err != nil {
+		// not cached, the receipt may show up later
+		return nil, &CallError{Method: p.method, Err: err}
+	}
+	if p.contract.chain.verifyResults {
+		if err := p.contract.chain.VerifyResult(ctx, p.Request, p.Hash); err != nil {
+			return p.setResult(receipt, &CallError{Method: p.method, Err: err})
+		}
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return p.setResult(receipt, &CallError{Method: p.method, Err: p.contract.callbackError(ctx, receipt)})
+	}
+	return p.setResult(receipt, nil)
+}
+
+// result returns the cached outcome of Wait, a nil receipt if there is none.
+func (p *PendingRequest) result() (*types.Receipt, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.receipt, p.err
+}
+
+// setResult caches the outcome of Wait, unless a concurrent call already did.
+func (p *PendingRequest) setResult(receipt *types.Receipt, err error) (*types.Receipt, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.receipt == nil {
+		p.receipt, p.err = receipt, err
+	}
+	return p.receipt, p.err
+}
+
+// Verify checks that the SUAVE transaction of the request was signed by its kettle
+// and wraps the signed request, see Chain.VerifyResult.
+func (p *PendingRequest) Verify(ctx context.Context) error {
+	if p.Hash == (common.Hash{}) {
+		return &CallError{Method: p.method, Err: ErrNotSent}
+	}
+	if err := p.contract.chain.VerifyResult(ctx, p.Request, p.Hash); err != nil {
+		return &CallError{Method: p.method, Err: err}
+	}
+	return nil
+}
+
+// Receipt returns the receipt of the request if a call to Wait already got it, nil otherwise.
+func (p *PendingRequest) Receipt() *types.Receipt {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.receipt
+}
+
+// SendConfidentialRequestAsync sends the confidential request to the kettle and returns
+// once the kettle accepts it, without waiting for the callback to be included.
+func (c *Contract) SendConfidentialRequestAsync(ctx context.Context, method string, args []interface{}, confidentialBytes []byte, opts ...RequestOption) (*PendingRequest, error) {
+	calldata, err := c.Abi.Pack(method, args...)
+	if err != nil {
+		return nil, &CallError{Method: method, Err: err}
+	}
+
+	cfg := newRequestConfig(opts)
+	if cfg.kettle == nil {
+		cfg.kettle = c.kettle
+	}
+	request, hash, err := c.chain.sendConfidentialRequest(ctx, c.account, c.addr, calldata, confidentialBytes, cfg)
+	if err != nil {
+		return nil, &CallError{Method: method, Err: c.decodeRevert(err)}
+	}
+
+	if !cfg.noSend {
+		c.chain.logTx(hash)
+	}
+
+	return &PendingRequest{Hash: hash, Request: request, contract: c, method: method}, nil
+}
+
+// ConfidentialRequest is a request of SendConfidentialRequests.
+type ConfidentialRequest struct {
+	Contract           *Contract
+	Method             string
+	Args               []interface{}
+	ConfidentialInputs []byte
+	Options            []RequestOption
+}
+
+// RequestResult is the outcome of a request of SendConfidentialRequests.
+type RequestResult struct {
+	// Hash is the hash of the callback transaction, zero if the kettle rejected the request.
+	Hash common.Hash
+
+	Receipt *types.Receipt
+	Err     error
+}
+
+// SendConfidentialRequests sends the requests back-to-back, without waiting for
+// the callbacks in between, and then waits for all of them. The results are in
+// the order of the requests. A failed request does not abort the batch, its error
+// is reported in its result and its nonce is reused by the next request of the account.
+func SendConfidentialRequests(ctx context.Context, requests []ConfidentialRequest) []RequestResult {
+	results := make([]RequestResult, len(requests))
+
+	pending := make([]*PendingRequest, len(requests))
+	for i, req := range requests {
+		p, err := req.Contract.SendConfidentialRequestAsync(ctx, req.Method, req.Args, req.ConfidentialInputs, req.Options...)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		pending[i] = p
+		results[i].Hash = p.Hash
+	}
+
+	for i, p := range pending {
+		if p == nil {
+			continue
+		}
+		results[i].Receipt, results[i].Err = p.Wait(ctx)
+	}
+	return results
+}
@@ -0,0 +1,313 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+const testRequestAbi = `[
+	{"type": "function", "name": "submit", "inputs": [{"name": "id", "type": "uint64"}], "outputs": [{"name": "", "type": "bytes"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "onSubmit", "inputs": [{"name": "id", "type": "uint64"}], "outputs": [], "stateMutability": "nonpayable"},
+	{"type": "event", "name": "Submitted", "inputs": [{"name": "id", "type": "uint64", "indexed": true}], "anonymous": false}
+]`
+
+// testKettleNode serves the "eth" methods of a SUAVE node with a kettle. It
+// includes the SUAVE transaction of a confidential request right away, with a
+// failed receipt if its confidential inputs are "fail", and rejects the request
+// if they are "reject".
+type testKettleNode struct {
+	kettle  *PrivKey
+	chainID *big.Int
+	onchain *abi.Method
+
+	mu       sync.Mutex
+	txs      map[common.Hash]*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	lookups  map[common.Hash]int
+}
+
+func newTestKettleNode(t *testing.T, contract *abi.ABI) *testKettleNode {
+	onchain := contract.Methods["onSubmit"]
+	return &testKettleNode{
+		kettle:   GeneratePrivKey(),
+		chainID:  big.NewInt(16813125),
+		onchain:  &onchain,
+		txs:      map[common.Hash]*types.Transaction{},
+		receipts: map[common.Hash]*types.Receipt{},
+		lookups:  map[common.Hash]int{},
+	}
+}
+
+func (n *testKettleNode) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(n.chainID)
+}
+
+func (n *testKettleNode) GetTransactionCount(addr common.Address, block string) hexutil.Uint64 {
+	return 0
+}
+
+func (n *testKettleNode) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1))
+}
+
+func (n *testKettleNode) EstimateGas(args map[string]interface{}) hexutil.Uint64 {
+	return 100000
+}
+
+func (n *testKettleNode) BlockNumber() hexutil.Uint64 {
+	return 2
+}
+
+func (n *testKettleNode) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	request := new(types.Transaction)
+	if err := request.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	ccr, ok := types.CastTxInner[*types.ConfidentialComputeRequest](request)
+	if !ok {
+		return common.Hash{}, errors.New("not a confidential request")
+	}
+	if string(ccr.ConfidentialInputs) == "reject" {
+		return common.Hash{}, errors.New("confidential request rejected")
+	}
+
+	id := new(big.Int).SetBytes(ccr.Data[4:]).Uint64()
+	callback, err := n.onchain.Inputs.Pack(id)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	record := ccr.ConfidentialComputeRecord
+	record.V, record.R, record.S = request.RawSignatureValues()
+	result, err := types.SignTx(types.NewTx(&types.SuaveTransaction{
+		ConfidentialComputeRequest: record,
+		ConfidentialComputeResult:  append(append([]byte{}, n.onchain.ID...), callback...),
+	}), types.NewSuaveSigner(n.chainID), n.kettle.Priv)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	status := types.ReceiptStatusSuccessful
+	if string(ccr.ConfidentialInputs) == "fail" {
+		status = types.ReceiptStatusFailed
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.txs[result.Hash()] = result
+	n.receipts[result.Hash()] = &types.Receipt{
+		Type:        types.SuaveTxType,
+		Status:      status,
+		TxHash:      result.Hash(),
+		GasUsed:     21000,
+		BlockNumber: big.NewInt(2),
+		Logs:        []*types.Log{},
+	}
+	return result.Hash(), nil
+}
+
+func (n *testKettleNode) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.lookups[hash]++
+	return n.receipts[hash]
+}
+
+func (n *testKettleNode) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.txs[hash]
+}
+
+func (n *testKettleNode) Call(args map[string]interface{}, block string) (hexutil.Bytes, error) {
+	return nil, errors.New("execution reverted")
+}
+
+func (n *testKettleNode) receiptLookups(hash common.Hash) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.lookups[hash]
+}
+
+// newTestRequestContract returns a contract on a chain served by a testKettleNode.
+func newTestRequestContract(t *testing.T) (*Contract, *testKettleNode) {
+	t.Helper()
+
+	contractAbi, err := abi.JSON(strings.NewReader(testRequestAbi))
+	require.NoError(t, err)
+	node := newTestKettleNode(t, &contractAbi)
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", node))
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+
+	chain := &Chain{
+		rpc:        client,
+		chainID:    node.chainID,
+		suave:      true,
+		kettleAddr: node.kettle.Address(),
+		policy:     FailoverPolicy(),
+		nonces:     newNonceManager(),
+	}
+	contract := &Contract{
+		chain:   chain,
+		account: GeneratePrivKey(),
+		addr:    common.HexToAddress("0x00000000000000000000000000000000000000c1"),
+		Abi:     &contractAbi,
+	}
+	return contract, node
+}
+
+func TestSendConfidentialRequests(t *testing.T) {
+	contract, _ := newTestRequestContract(t)
+
+	requests := []ConfidentialRequest{
+		{Contract: contract, Method: "submit", Args: []interface{}{uint64(1)}},
+		{Contract: contract, Method: "submit", Args: []interface{}{uint64(2)}, ConfidentialInputs: []byte("reject")},
+		{Contract: contract, Method: "submit", Args: []interface{}{uint64(3)}, ConfidentialInputs: []byte("fail")},
+		{Contract: contract, Method: "unknown"},
+		{Contract: contract, Method: "submit", Args: []interface{}{uint64(4)}},
+	}
+	results := SendConfidentialRequests(context.Background(), requests)
+	require.Len(t, results, len(requests))
+
+	cases := []struct {
+		name        string
+		wantErr     string
+		wantReceipt bool
+		wantStatus  uint64
+	}{
+		{name: "sent", wantReceipt: true, wantStatus: types.ReceiptStatusSuccessful},
+		{name: "rejected by the kettle", wantErr: "confidential request rejected"},
+		{name: "failed callback", wantErr: "callback onSubmit", wantReceipt: true, wantStatus: types.ReceiptStatusFailed},
+		{name: "unknown method", wantErr: "method 'unknown' not found"},
+		{name: "sent after the failures", wantReceipt: true, wantStatus: types.ReceiptStatusSuccessful},
+	}
+
+	var nonces []uint64
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := results[i]
+			if tc.wantErr != "" {
+				require.ErrorContains(t, res.Err, tc.wantErr)
+				var callErr *CallError
+				require.ErrorAs(t, res.Err, &callErr)
+			} else {
+				require.NoError(t, res.Err)
+			}
+			if !tc.wantReceipt {
+				require.Equal(t, common.Hash{}, res.Hash)
+				require.Nil(t, res.Receipt)
+				return
+			}
+			// the results are in the order of the requests
+			require.NotNil(t, res.Receipt)
+			require.Equal(t, res.Hash, res.Receipt.TxHash)
+			require.Equal(t, tc.wantStatus, res.Receipt.Status)
+
+			txn, _, err := contract.chain.RPC().TransactionByHash(context.Background(), res.Hash)
+			require.NoError(t, err)
+			suaveTx, _ := types.CastTxInner[*types.SuaveTransaction](txn)
+			nonces = append(nonces, suaveTx.ConfidentialComputeRequest.Nonce)
+		})
+	}
+
+	// the nonces of the rejected requests are reused
+	require.Equal(t, []uint64{0, 1, 2}, nonces)
+}
+
+func TestPendingRequestWait(t *testing.T) {
+	t.Run("caches the receipt", func(t *testing.T) {
+		contract, node := newTestRequestContract(t)
+
+		pending, err := contract.SendConfidentialRequestAsync(context.Background(), "submit", []interface{}{uint64(1)}, nil)
+		require.NoError(t, err)
+		require.Nil(t, pending.Receipt())
+
+		receipt, err := pending.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, pending.Hash, receipt.TxHash)
+		require.Same(t, receipt, pending.Receipt())
+
+		again, err := pending.Wait(context.Background())
+		require.NoError(t, err)
+		require.Same(t, receipt, again)
+		require.Equal(t, 1, node.receiptLookups(pending.Hash))
+	})
+
+	t.Run("caches the callback error", func(t *testing.T) {
+		contract, node := newTestRequestContract(t)
+
+		pending, err := contract.SendConfidentialRequestAsync(context.Background(), "submit", []interface{}{uint64(1)}, []byte("fail"))
+		require.NoError(t, err)
+
+		receipt, err := pending.Wait(context.Background())
+		require.ErrorIs(t, err, ErrTransactionFailed)
+		var cbErr *CallbackError
+		require.ErrorAs(t, err, &cbErr)
+		require.Equal(t, "onSubmit", cbErr.Method)
+		require.Equal(t, types.ReceiptStatusFailed, receipt.Status)
+
+		againReceipt, againErr := pending.Wait(context.Background())
+		require.Same(t, receipt, againReceipt)
+		require.Same(t, err, againErr)
+		require.Equal(t, 1, node.receiptLookups(pending.Hash))
+	})
+
+	t.Run("not sent", func(t *testing.T) {
+		contract, _ := newTestRequestContract(t)
+
+		pending, err := contract.SendConfidentialRequestAsync(context.Background(), "submit", []interface{}{uint64(1)}, nil, WithNoSend())
+		require.NoError(t, err)
+		require.Equal(t, common.Hash{}, pending.Hash)
+		require.NotNil(t, pending.Request)
+
+		_, err = pending.Wait(context.Background())
+		require.ErrorIs(t, err, ErrNotSent)
+		require.ErrorIs(t, pending.Verify(context.Background()), ErrNotSent)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		contract, _ := newTestRequestContract(t)
+
+		pending, err := contract.SendConfidentialRequestAsync(context.Background(), "submit", []interface{}{uint64(1)}, nil)
+		require.NoError(t, err)
+
+		const waiters = 10
+		receipts := make(chan *types.Receipt, waiters)
+		var wg sync.WaitGroup
+		for i := 0; i < waiters; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				receipt, err := pending.Wait(context.Background())
+				if err == nil {
+					receipts <- receipt
+				}
+			}()
+		}
+		wg.Wait()
+		close(receipts)
+
+		// all the waiters get the receipt cached by the first one
+		require.Len(t, receipts, waiters)
+		for receipt := range receipts {
+			require.Same(t, pending.Receipt(), receipt)
+		}
+	})
+}
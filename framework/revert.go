@@ -0,0 +1,232 @@
+package framework
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/suave/artifacts"
+)
+
+var (
+	errorSelector          = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector          = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+	peekerRevertedSelector = crypto.Keccak256([]byte("PeekerReverted(address,bytes)"))[:4]
+)
+
+// panicReasons are the human-readable descriptions of the Solidity Panic(uint256) codes.
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// RevertError is a decoded Solidity revert.
+type RevertError struct {
+	// Name is the name of the error: "Error" for Error(string), "Panic" for
+	// Panic(uint256), the name of a custom error, or empty if it is unknown.
+	Name string
+
+	// Selector is the 4-byte selector of the error.
+	Selector [4]byte
+
+	// Args are the decoded arguments of the error.
+	Args []interface{}
+
+	// Reason is a human-readable description of the error.
+	Reason string
+
+	// Peeker is the address of the precompile that reverted if the error is a PeekerReverted.
+	Peeker common.Address
+
+	// Inner is the revert nested inside a PeekerReverted error, if any.
+	Inner *RevertError
+
+	// Data is the raw revert data.
+	Data []byte
+}
+
+func (e *RevertError) Error() string {
+	return "execution reverted: " + e.Reason
+}
+
+// PanicCode returns the code of a Panic(uint256) error.
+func (e *RevertError) PanicCode() (uint64, bool) {
+	if e.Name != "Panic" || len(e.Args) != 1 {
+		return 0, false
+	}
+	code, ok := e.Args[0].(*big.Int)
+	if !ok {
+		return 0, false
+	}
+	return code.Uint64(), true
+}
+
+// RevertDecoder decodes revert data using the errors of a set of ABIs.
+type RevertDecoder struct {
+	abis []*abi.ABI
+}
+
+// NewRevertDecoder returns a decoder that knows about the errors of the given ABIs
+// and of the SUAVE precompiles.
+func NewRevertDecoder(abis ...*abi.ABI) *RevertDecoder {
+	return &RevertDecoder{abis: append(append([]*abi.ABI{}, abis...), artifacts.SuaveAbi)}
+}
+
+// DecodeError extracts the revert data carried by err and decodes it. It returns
+// nil if err does not carry any revert data.
+func (d *RevertDecoder) DecodeError(err error) *RevertError {
+	data, ok := revertData(err)
+	if !ok {
+		return nil
+	}
+	return d.Decode(data)
+}
+
+// Decode decodes the revert data. Data that does not match any known error
+// is returned as a *RevertError with an empty Name.
+func (d *RevertDecoder) Decode(data []byte) *RevertError {
+	revertErr := &RevertError{Data: data}
+	if len(data) < 4 {
+		revertErr.Reason = fmt.Sprintf("unknown error 0x%x", data)
+		return revertErr
+	}
+	copy(revertErr.Selector[:], data[:4])
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			break
+		}
+		revertErr.Name = "Error"
+		revertErr.Args = []interface{}{reason}
+		revertErr.Reason = reason
+		return revertErr
+
+	case bytes.Equal(data[:4], panicSelector):
+		unpacked, err := (abi.Arguments{{Type: uint256Type}}).Unpack(data[4:])
+		if err != nil {
+			break
+		}
+		code, _ := unpacked[0].(*big.Int)
+		reason := "unknown panic"
+		if known, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			reason = known
+		}
+		revertErr.Name = "Panic"
+		revertErr.Args = unpacked
+		revertErr.Reason = fmt.Sprintf("panic: %s (0x%x)", reason, code)
+		return revertErr
+
+	case bytes.Equal(data[:4], peekerRevertedSelector):
+		unpacked, err := artifacts.SuaveAbi.Errors["PeekerReverted"].Inputs.Unpack(data[4:])
+		if err != nil {
+			break
+		}
+		revertErr.Name = "PeekerReverted"
+		revertErr.Args = unpacked
+		revertErr.Peeker, _ = unpacked[0].(common.Address)
+
+		payload, _ := unpacked[1].([]byte)
+		if inner := d.decodeKnown(payload); inner != nil {
+			revertErr.Inner = inner
+			revertErr.Reason = fmt.Sprintf("peeker %s reverted: %s", revertErr.Peeker.Hex(), inner.Reason)
+		} else {
+			revertErr.Reason = fmt.Sprintf("peeker %s reverted: %s", revertErr.Peeker.Hex(), payload)
+		}
+		return revertErr
+	}
+
+	if custom := d.decodeCustom(data); custom != nil {
+		return custom
+	}
+	revertErr.Reason = fmt.Sprintf("unknown error 0x%x", data)
+	return revertErr
+}
+
+// decodeKnown decodes data only if it matches one of the known errors.
+func (d *RevertDecoder) decodeKnown(data []byte) *RevertError {
+	if revertErr := d.Decode(data); revertErr.Name != "" {
+		return revertErr
+	}
+	return nil
+}
+
+// decodeCustom decodes data as one of the custom errors of the ABIs.
+func (d *RevertDecoder) decodeCustom(data []byte) *RevertError {
+	for _, contractAbi := range d.abis {
+		if contractAbi == nil {
+			continue
+		}
+		for name, abiErr := range contractAbi.Errors {
+			if !bytes.Equal(data[:4], abiErr.ID[:4]) {
+				continue
+			}
+			unpacked, err := abiErr.Inputs.Unpack(data[4:])
+			if err != nil {
+				continue
+			}
+
+			args := make([]string, len(unpacked))
+			for i, arg := range unpacked {
+				args[i] = fmt.Sprint(arg)
+			}
+
+			revertErr := &RevertError{
+				Name:   name,
+				Args:   unpacked,
+				Reason: fmt.Sprintf("%s(%s)", name, strings.Join(args, ", ")),
+				Data:   data,
+			}
+			copy(revertErr.Selector[:], data[:4])
+			return revertErr
+		}
+	}
+	return nil
+}
+
+var uint256Type, _ = abi.NewType("uint256", "", nil)
+
+// revertData returns the revert data carried by an RPC error, either as
+// error data or encoded in the error message.
+func revertData(err error) ([]byte, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if str, ok := dataErr.ErrorData().(string); ok {
+			if data, err := hexutil.Decode(str); err == nil && len(data) != 0 {
+				return data, true
+			}
+		}
+	}
+
+	errMsg := err.Error()
+	idx := strings.Index(errMsg, executionRevertedPrefix)
+	if idx == -1 {
+		return nil, false
+	}
+	data, decErr := hex.DecodeString(errMsg[idx+len(executionRevertedPrefix):])
+	if decErr != nil {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,200 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/suave/artifacts"
+	"github.com/stretchr/testify/require"
+)
+
+const testErrorsAbi = `[
+	{"type": "error", "name": "InsufficientBalance", "inputs": [{"name": "available", "type": "uint256"}, {"name": "required", "type": "uint256"}]},
+	{"type": "error", "name": "Unauthorized", "inputs": []}
+]`
+
+func packError(t *testing.T, selector []byte, types []string, args ...interface{}) []byte {
+	t.Helper()
+
+	var arguments abi.Arguments
+	for _, typ := range types {
+		abiType, err := abi.NewType(typ, "", nil)
+		require.NoError(t, err)
+		arguments = append(arguments, abi.Argument{Type: abiType})
+	}
+	packed, err := arguments.Pack(args...)
+	require.NoError(t, err)
+	return append(append([]byte{}, selector...), packed...)
+}
+
+func TestRevertDecoderDecode(t *testing.T) {
+	contractAbi, err := abi.JSON(strings.NewReader(testErrorsAbi))
+	require.NoError(t, err)
+	decoder := NewRevertDecoder(&contractAbi)
+
+	peeker := common.HexToAddress("0x0000000000000000000000000000000042100000")
+	errorData := packError(t, errorSelector, []string{"string"}, "not enough funds")
+	customID := contractAbi.Errors["InsufficientBalance"].ID
+	unauthorizedID := contractAbi.Errors["Unauthorized"].ID
+
+	cases := []struct {
+		name       string
+		data       []byte
+		wantName   string
+		wantReason string
+		wantPeeker common.Address
+		wantInner  string
+	}{
+		{
+			name:       "error string",
+			data:       errorData,
+			wantName:   "Error",
+			wantReason: "not enough funds",
+		},
+		{
+			name:       "known panic",
+			data:       packError(t, panicSelector, []string{"uint256"}, big.NewInt(0x11)),
+			wantName:   "Panic",
+			wantReason: "panic: arithmetic underflow or overflow (0x11)",
+		},
+		{
+			name:       "unknown panic",
+			data:       packError(t, panicSelector, []string{"uint256"}, big.NewInt(0x99)),
+			wantName:   "Panic",
+			wantReason: "panic: unknown panic (0x99)",
+		},
+		{
+			name:       "custom error",
+			data:       packError(t, customID[:4], []string{"uint256", "uint256"}, big.NewInt(1), big.NewInt(2)),
+			wantName:   "InsufficientBalance",
+			wantReason: "InsufficientBalance(1, 2)",
+		},
+		{
+			name:       "custom error without arguments",
+			data:       unauthorizedID[:4],
+			wantName:   "Unauthorized",
+			wantReason: "Unauthorized()",
+		},
+		{
+			name:       "peeker reverted with a nested error",
+			data:       packError(t, peekerRevertedSelector, []string{"address", "bytes"}, peeker, errorData),
+			wantName:   "PeekerReverted",
+			wantReason: fmt.Sprintf("peeker %s reverted: not enough funds", peeker.Hex()),
+			wantPeeker: peeker,
+			wantInner:  "Error",
+		},
+		{
+			name:       "peeker reverted with a message",
+			data:       packError(t, peekerRevertedSelector, []string{"address", "bytes"}, peeker, []byte("bad input")),
+			wantName:   "PeekerReverted",
+			wantReason: fmt.Sprintf("peeker %s reverted: bad input", peeker.Hex()),
+			wantPeeker: peeker,
+		},
+		{
+			name:       "unknown selector",
+			data:       []byte{0xde, 0xad, 0xbe, 0xef, 0x01},
+			wantReason: "unknown error 0xdeadbeef01",
+		},
+		{
+			name:       "short data",
+			data:       []byte{0x01, 0x02},
+			wantReason: "unknown error 0x0102",
+		},
+		{
+			name:       "truncated error string",
+			data:       errorData[:20],
+			wantReason: fmt.Sprintf("unknown error 0x%x", errorData[:20]),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			revertErr := decoder.Decode(tc.data)
+			require.Equal(t, tc.wantName, revertErr.Name)
+			require.Equal(t, tc.wantReason, revertErr.Reason)
+			require.Equal(t, tc.wantPeeker, revertErr.Peeker)
+			require.Equal(t, tc.data, revertErr.Data)
+			if tc.wantInner == "" {
+				require.Nil(t, revertErr.Inner)
+			} else {
+				require.NotNil(t, revertErr.Inner)
+				require.Equal(t, tc.wantInner, revertErr.Inner.Name)
+			}
+		})
+	}
+}
+
+func TestRevertErrorPanicCode(t *testing.T) {
+	decoder := NewRevertDecoder()
+
+	code, ok := decoder.Decode(packError(t, panicSelector, []string{"uint256"}, big.NewInt(0x32))).PanicCode()
+	require.True(t, ok)
+	require.Equal(t, uint64(0x32), code)
+
+	_, ok = decoder.Decode(packError(t, errorSelector, []string{"string"}, "boom")).PanicCode()
+	require.False(t, ok)
+}
+
+type testDataError struct {
+	msg  string
+	data interface{}
+}
+
+func (e *testDataError) Error() string          { return e.msg }
+func (e *testDataError) ErrorData() interface{} { return e.data }
+
+func TestRevertDecoderDecodeError(t *testing.T) {
+	decoder := NewRevertDecoder()
+	errorData := packError(t, errorSelector, []string{"string"}, "boom")
+
+	cases := []struct {
+		name       string
+		err        error
+		wantReason string
+	}{
+		{
+			name:       "rpc error data",
+			err:        &testDataError{msg: "execution reverted", data: hexutil.Encode(errorData)},
+			wantReason: "boom",
+		},
+		{
+			name:       "wrapped rpc error data",
+			err:        fmt.Errorf("call failed: %w", &testDataError{msg: "execution reverted", data: hexutil.Encode(errorData)}),
+			wantReason: "boom",
+		},
+		{
+			name:       "data in the message",
+			err:        errors.New("failed to send: execution reverted: " + hexutil.Encode(errorData)),
+			wantReason: "boom",
+		},
+		{
+			name:       "suave precompile revert in the message",
+			err:        errors.New("execution reverted: " + hexutil.Encode(packError(t, peekerRevertedSelector, []string{"address", "bytes"}, common.Address{0x42}, []byte("oops")))),
+			wantReason: fmt.Sprintf("peeker %s reverted: oops", common.Address{0x42}.Hex()),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			revertErr := decoder.DecodeError(tc.err)
+			require.NotNil(t, revertErr)
+			require.Equal(t, tc.wantReason, revertErr.Reason)
+		})
+	}
+
+	require.Nil(t, decoder.DecodeError(nil))
+	require.Nil(t, decoder.DecodeError(errors.New("nonce too low")))
+	require.Nil(t, decoder.DecodeError(errors.New("execution reverted: 0xzz")))
+	require.Nil(t, decoder.DecodeError(&testDataError{msg: "execution reverted", data: 42}))
+}
+
+func TestRevertDecoderKnowsSuavePrecompiles(t *testing.T) {
+	id := artifacts.SuaveAbi.Errors["PeekerReverted"].ID
+	require.Equal(t, id[:4], peekerRevertedSelector)
+}
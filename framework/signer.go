@@ -0,0 +1,160 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer is an account that signs transactions and confidential requests.
+// The signatures are in the [R || S || V] format with V either 0 or 1.
+type Signer interface {
+	// Address returns the address of the account.
+	Address() common.Address
+
+	// SignTx signs a transaction or confidential request for the chain.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	// SignTypedData signs EIP-712 typed data.
+	SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error)
+
+	// SignHash signs a 32-byte hash.
+	SignHash(ctx context.Context, hash common.Hash) ([]byte, error)
+}
+
+var (
+	_ Signer = &PrivKey{}
+	_ Signer = &KeystoreSigner{}
+)
+
+// SignTx signs the transaction with the in-memory key.
+func (p *PrivKey) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return signTxHash(ctx, p, tx, chainID)
+}
+
+// SignTypedData signs the EIP-712 typed data with the in-memory key.
+func (p *PrivKey) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	return signTypedDataHash(ctx, p, data)
+}
+
+// SignHash signs the hash with the in-memory key.
+func (p *PrivKey) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	return crypto.Sign(hash[:], p.Priv)
+}
+
+// KeystoreSigner signs with an account of a go-ethereum encrypted keystore.
+type KeystoreSigner struct {
+	ks      *keystore.KeyStore
+	account accounts.Account
+}
+
+// NewKeystoreSigner returns a signer for the account of the keystore. It unlocks
+// the account until the keystore is closed, decrypting the key for each signature
+// is too slow with the standard scrypt parameters.
+func NewKeystoreSigner(ks *keystore.KeyStore, account accounts.Account, passphrase string) (*KeystoreSigner, error) {
+	if err := ks.Unlock(account, passphrase); err != nil {
+		return nil, fmt.Errorf("failed to unlock %s: %w", account.Address.Hex(), err)
+	}
+	return &KeystoreSigner{ks: ks, account: account}, nil
+}
+
+func (k *KeystoreSigner) Address() common.Address {
+	return k.account.Address
+}
+
+func (k *KeystoreSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return signTxHash(ctx, k, tx, chainID)
+}
+
+func (k *KeystoreSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	return signTypedDataHash(ctx, k, data)
+}
+
+func (k *KeystoreSigner) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	return k.ks.SignHash(k.account, hash[:])
+}
+
+// signTxHash signs the transaction with the signature of its hash.
+func signTxHash(ctx context.Context, s Signer, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.NewSuaveSigner(chainID)
+	if tx.Type() == types.BlobTxType {
+		// the SUAVE signer does not accept the blob transactions
+		signer = types.NewCancunSigner(chainID)
+	}
+	sig, err := s.SignHash(ctx, signer.Hash(tx))
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// signTypedDataHash signs the EIP-712 hash of the typed data.
+func signTypedDataHash(ctx context.Context, s Signer, data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	return s.SignHash(ctx, common.BytesToHash(hash))
+}
+
+// confidentialRecordTypedData returns the EIP-712 typed data signed by the
+// sender of a confidential request, types.CCREIP712Envelope with the message
+// values in their JSON form for the clef API.
+func confidentialRecordTypedData(record *types.ConfidentialComputeRecord) apitypes.TypedData {
+	envelope := types.CCREIP712Envelope(record)
+
+	data := apitypes.TypedData{
+		Types:       apitypes.Types{},
+		PrimaryType: envelope.PrimaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:              envelope.Domain.Name,
+			Version:           envelope.Domain.Version,
+			ChainId:           envelope.Domain.ChainId,
+			VerifyingContract: envelope.Domain.VerifyingContract,
+			Salt:              envelope.Domain.Salt,
+		},
+		Message: apitypes.TypedDataMessage{},
+	}
+	for name, fields := range envelope.Types {
+		for _, field := range fields {
+			data.Types[name] = append(data.Types[name], apitypes.Type{Name: field.Name, Type: field.Type})
+		}
+	}
+	for name, value := range envelope.Message {
+		data.Message[name] = typedDataValue(value)
+	}
+	return data
+}
+
+// typedDataValue converts a value of the message of the envelope to its JSON form.
+func typedDataValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case uint64:
+		return fmt.Sprint(v)
+	case *big.Int:
+		if v == nil {
+			return "0"
+		}
+		return v.String()
+	case *common.Address:
+		if v == nil {
+			return common.Address{}.Hex()
+		}
+		return v.Hex()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	}
+	return value
+}
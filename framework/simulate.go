@@ -0,0 +1,103 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SimulationResult is the outcome of the confidential computation of a request
+// executed with SimulateConfidentialRequest.
+type SimulationResult struct {
+	// Calldata is the calldata of the callback returned by the confidential computation.
+	Calldata []byte
+
+	// Selector is the 4-byte selector of the callback, zero if Calldata is shorter.
+	Selector [4]byte
+
+	// Method is the callback method and Args its decoded arguments. They are
+	// nil if the callback is not in the ABI of the contract.
+	Method *abi.Method
+	Args   []interface{}
+}
+
+// SimulateConfidentialRequest runs the confidential computation of a request with the
+// call endpoint of the kettle, without sending the request nor its callback. The
+// kettle does not return the logs emitted off-chain by the call, they are only
+// appended to the callback of the requests that are sent.
+func (c *Contract) SimulateConfidentialRequest(method string, args []interface{}, confidentialInputs []byte, opts ...RequestOption) *SimulationResult {
+	ctx, cancel := c.chain.defaultContext()
+	defer cancel()
+
+	res, err := c.SimulateConfidentialRequestE(ctx, method, args, confidentialInputs, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// SimulateConfidentialRequestE is like SimulateConfidentialRequest but returns an error
+// instead of panicking. The reverts of the confidential computation, including the
+// PeekerReverted errors of the precompiles, are decoded like in SendConfidentialRequestE.
+func (c *Contract) SimulateConfidentialRequestE(ctx context.Context, method string, args []interface{}, confidentialInputs []byte, opts ...RequestOption) (*SimulationResult, error) {
+	calldata, err := c.Abi.Pack(method, args...)
+	if err != nil {
+		return nil, &CallError{Method: method, Err: err}
+	}
+
+	if err := c.chain.Connect(ctx); err != nil {
+		return nil, &CallError{Method: method, Err: err}
+	}
+
+	cfg := newRequestConfig(opts)
+	if cfg.kettle == nil {
+		cfg.kettle = c.kettle
+	}
+	kettle := c.chain.selectKettles(c.addr, cfg.kettle)[0].Address
+	gas := cfg.gas
+	if gas == 0 {
+		gas = defaultGasLimit
+	}
+
+	callArgs := map[string]interface{}{
+		"from":               c.account.Address(),
+		"to":                 c.addr,
+		"gas":                hexutil.Uint64(gas),
+		"input":              hexutil.Bytes(calldata),
+		"isConfidential":     true,
+		"kettleAddress":      kettle,
+		"confidentialInputs": hexutil.Bytes(confidentialInputs),
+	}
+	if cfg.gasPrice != nil {
+		callArgs["gasPrice"] = (*hexutil.Big)(cfg.gasPrice)
+	}
+	if cfg.value != nil {
+		callArgs["value"] = (*hexutil.Big)(cfg.value)
+	}
+
+	var output hexutil.Bytes
+	if err := c.chain.kettleRPC(kettle).CallContext(ctx, &output, "eth_call", callArgs, "latest"); err != nil {
+		return nil, &CallError{Method: method, Err: c.decodeRevert(err)}
+	}
+
+	// the confidential methods return the callback calldata abi encoded as bytes
+	res := &SimulationResult{Calldata: output}
+	if unpacked, err := (abi.Arguments{{Type: bytesType}}).Unpack(output); err == nil {
+		res.Calldata = unpacked[0].([]byte)
+	}
+
+	if len(res.Calldata) >= 4 {
+		copy(res.Selector[:], res.Calldata[:4])
+		if callback, err := c.Abi.MethodById(res.Calldata[:4]); err == nil {
+			values, err := callback.Inputs.Unpack(res.Calldata[4:])
+			if err != nil {
+				return nil, &CallError{Method: method, Err: err}
+			}
+			res.Method, res.Args = callback, values
+		}
+	}
+	return res, nil
+}
+
+var bytesType, _ = abi.NewType("bytes", "", nil)
@@ -0,0 +1,409 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// defaultGasLimit is the gas limit of the confidential requests whose gas cannot
+// be estimated. It matches the default of the suave-geth sdk.
+const defaultGasLimit = uint64(10000000)
+
+// confidentialGasFactor scales the gas estimated for a confidential request. The
+// kettle only measures the confidential computation, not the callback.
+const confidentialGasFactor = 2
+
+// receiptPollInterval is how often the node is queried while waiting for a receipt.
+const receiptPollInterval = 100 * time.Millisecond
+
+// defaultContext returns the context used by the helpers that do not take one.
+func (c *Chain) defaultContext() (context.Context, context.CancelFunc) {
+	if c.timeout == 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// signer returns the transaction signer for the chain.
+func (c *Chain) signer(ctx context.Context) (types.Signer, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return c.txSigner(), nil
+}
+
+// sendTransaction fills the missing fields of txn, signs it with acct and
+// submits it to the chain. If nonce is nil, the next nonce of acct is used.
+func (c *Chain) sendTransaction(ctx context.Context, acct Signer, txn *types.LegacyTx, nonce *uint64) (common.Hash, error) {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	senderAddr := acct.Address()
+
+	if txn.GasPrice == nil {
+		gasPrice, err := clt.SuggestGasPrice(ctx)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		txn.GasPrice = gasPrice
+	}
+
+	if txn.Gas == 0 {
+		gasLimit, err := clt.EstimateGas(ctx, ethereum.CallMsg{
+			From:     senderAddr,
+			To:       txn.To,
+			GasPrice: txn.GasPrice,
+			Value:    txn.Value,
+			Data:     txn.Data,
+		})
+		if err != nil {
+			return common.Hash{}, err
+		}
+		txn.Gas = gasLimit
+	}
+
+	signer, err := c.signer(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	send := func(nonce uint64) (common.Hash, error) {
+		txn.Nonce = nonce
+		signed, err := acct.SignTx(ctx, types.NewTx(txn), signer.ChainID())
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return c.sendRawTransaction(ctx, signed)
+	}
+	if nonce != nil {
+		return send(*nonce)
+	}
+	return c.sendWithNonce(ctx, senderAddr, send)
+}
+
+// fillTx returns a copy of txdata with the fields filled by SignTxE. nonce replaces
+// the nonce of txdata if it is not nil.
+func (c *Chain) fillTx(ctx context.Context, from common.Address, txdata types.TxData, nonce *uint64) (types.TxData, error) {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fillGasPrice := func(gasPrice **big.Int) func() error {
+		return func() (err error) {
+			if *gasPrice == nil {
+				*gasPrice, err = clt.SuggestGasPrice(ctx)
+			}
+			return err
+		}
+	}
+	fillFeeCaps := func(tip, feeCap **big.Int) func() error {
+		return func() (err error) {
+			if *tip == nil {
+				if *tip, err = clt.SuggestGasTipCap(ctx); err != nil {
+					return err
+				}
+				// a suggested tip above the fee cap would make the
+				// transaction invalid, it cannot tip more than the cap
+				if *feeCap != nil && (*tip).Cmp(*feeCap) > 0 {
+					*tip = new(big.Int).Set(*feeCap)
+				}
+			}
+			if *feeCap == nil {
+				head, err := clt.HeaderByNumber(ctx, nil)
+				if err != nil {
+					return err
+				}
+				if head.BaseFee == nil {
+					return fmt.Errorf("chain %s does not support EIP-1559 transactions", c.chainID)
+				}
+				*feeCap = new(big.Int).Add(*tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+			}
+			return nil
+		}
+	}
+	// the call is built once the fees are filled
+	fillGas := func(gas *uint64, call func() ethereum.CallMsg) func() error {
+		return func() (err error) {
+			if *gas == 0 {
+				msg := call()
+				msg.From = from
+				*gas, err = clt.EstimateGas(ctx, msg)
+			}
+			return err
+		}
+	}
+	fill := func(steps ...func() error) error {
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch tx := txdata.(type) {
+	case *types.LegacyTx:
+		cpy := *tx
+		if nonce != nil {
+			cpy.Nonce = *nonce
+		}
+		return &cpy, fill(
+			fillGasPrice(&cpy.GasPrice),
+			fillGas(&cpy.Gas, func() ethereum.CallMsg {
+				return ethereum.CallMsg{To: cpy.To, GasPrice: cpy.GasPrice, Value: cpy.Value, Data: cpy.Data}
+			}),
+		)
+
+	case *types.AccessListTx:
+		cpy := *tx
+		if cpy.ChainID == nil {
+			cpy.ChainID = new(big.Int).Set(c.chainID)
+		}
+		if nonce != nil {
+			cpy.Nonce = *nonce
+		}
+		return &cpy, fill(
+			fillGasPrice(&cpy.GasPrice),
+			fillGas(&cpy.Gas, func() ethereum.CallMsg {
+				return ethereum.CallMsg{To: cpy.To, GasPrice: cpy.GasPrice, Value: cpy.Value, Data: cpy.Data, AccessList: cpy.AccessList}
+			}),
+		)
+
+	case *types.DynamicFeeTx:
+		cpy := *tx
+		if cpy.ChainID == nil {
+			cpy.ChainID = new(big.Int).Set(c.chainID)
+		}
+		if nonce != nil {
+			cpy.Nonce = *nonce
+		}
+		return &cpy, fill(
+			fillFeeCaps(&cpy.GasTipCap, &cpy.GasFeeCap),
+			fillGas(&cpy.Gas, func() ethereum.CallMsg {
+				return ethereum.CallMsg{To: cpy.To, GasTipCap: cpy.GasTipCap, GasFeeCap: cpy.GasFeeCap, Value: cpy.Value, Data: cpy.Data, AccessList: cpy.AccessList}
+			}),
+		)
+
+	case *types.BlobTx:
+		cpy := *tx
+		if cpy.ChainID == nil {
+			cpy.ChainID = uint256.MustFromBig(c.chainID)
+		}
+		tip, feeCap := cpy.GasTipCap.ToBig(), cpy.GasFeeCap.ToBig()
+		// the blobs are not part of the estimate, the gas of a blob
+		// transaction only pays for its execution
+		if nonce != nil {
+			cpy.Nonce = *nonce
+		}
+		err := fill(
+			fillFeeCaps(&tip, &feeCap),
+			fillGas(&cpy.Gas, func() ethereum.CallMsg {
+				return ethereum.CallMsg{To: cpy.To, GasTipCap: tip, GasFeeCap: feeCap, Value: cpy.Value.ToBig(), Data: cpy.Data, AccessList: cpy.AccessList}
+			}),
+			func() error {
+				if cpy.BlobFeeCap != nil {
+					return nil
+				}
+				var blobFee hexutil.Big
+				if err := clt.Client().CallContext(ctx, &blobFee, "eth_blobBaseFee"); err != nil {
+					return fmt.Errorf("failed to get the blob base fee, set BlobFeeCap: %w", err)
+				}
+				cpy.BlobFeeCap = uint256.MustFromBig(new(big.Int).Mul(blobFee.ToInt(), big.NewInt(2)))
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		cpy.GasTipCap, cpy.GasFeeCap = uint256.MustFromBig(tip), uint256.MustFromBig(feeCap)
+		return &cpy, nil
+	}
+	return nil, fmt.Errorf("cannot sign transactions of type %T, use SendConfidentialRequest for confidential requests", txdata)
+}
+
+// sendConfidentialRequest builds a confidential compute request for the contract
+// at 'to', signs it with acct and, unless cfg.noSend is set, submits it to the kettle
+// selected by cfg.kettle or the kettle policy. If a kettle cannot be reached, the
+// request is signed again for the next kettle of the policy and sent to it.
+// It returns the signed request and the hash of the callback transaction.
+func (c *Chain) sendConfidentialRequest(ctx context.Context, acct Signer, to common.Address, calldata, confidentialBytes []byte, cfg *requestConfig) (*types.Transaction, common.Hash, error) {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	signer, err := c.signer(ctx)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+
+	kettles := c.selectKettles(to, cfg.kettle)
+	record := types.ConfidentialComputeRecord{
+		KettleAddress: kettles[0].Address,
+		To:            &to,
+		GasPrice:      cfg.gasPrice,
+		Gas:           cfg.gas,
+		Value:         cfg.value,
+		Data:          calldata,
+	}
+	if record.GasPrice == nil {
+		if record.GasPrice, err = clt.SuggestGasPrice(ctx); err != nil {
+			return nil, common.Hash{}, err
+		}
+	}
+	if record.Gas == 0 {
+		record.Gas = c.estimateConfidentialGas(ctx, acct.Address(), &record, confidentialBytes)
+	}
+	if c.eip712 {
+		record.ChainID = signer.ChainID()
+		record.IsEIP712 = true
+	}
+
+	var request *types.Transaction
+	send := func(nonce uint64) (common.Hash, error) {
+		record.Nonce = nonce
+		var sendErr error
+		for _, kettle := range kettles {
+			record.KettleAddress = kettle.Address
+			signed, err := signConfidentialRequest(ctx, acct, signer.ChainID(), record, confidentialBytes)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			request = signed
+			if cfg.noSend {
+				return common.Hash{}, nil
+			}
+			hash, err := c.sendRawTransaction(ctx, signed)
+			if err == nil || !isUnavailable(ctx, err) {
+				return hash, err
+			}
+			log.Printf("kettle %s unavailable: %v", kettle.Address.Hex(), err)
+			sendErr = err
+		}
+		return common.Hash{}, sendErr
+	}
+
+	var hash common.Hash
+	if cfg.nonce != nil {
+		hash, err = send(*cfg.nonce)
+	} else {
+		hash, err = c.sendWithNonce(ctx, acct.Address(), send)
+	}
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return request, hash, nil
+}
+
+// estimateConfidentialGas estimates the gas limit of a confidential request with
+// the kettle. If the estimation fails it falls back to defaultGasLimit, so that
+// sending the request reports the actual error.
+func (c *Chain) estimateConfidentialGas(ctx context.Context, from common.Address, record *types.ConfidentialComputeRecord, confidentialBytes []byte) uint64 {
+	args := map[string]interface{}{
+		"from":               from,
+		"to":                 record.To,
+		"gasPrice":           (*hexutil.Big)(record.GasPrice),
+		"input":              hexutil.Bytes(record.Data),
+		"isConfidential":     true,
+		"kettleAddress":      record.KettleAddress,
+		"confidentialInputs": hexutil.Bytes(confidentialBytes),
+	}
+	if record.Value != nil {
+		args["value"] = (*hexutil.Big)(record.Value)
+	}
+
+	var gas hexutil.Uint64
+	if err := c.kettleRPC(record.KettleAddress).CallContext(ctx, &gas, "eth_estimateGas", args); err != nil {
+		return defaultGasLimit
+	}
+	return uint64(gas) * confidentialGasFactor
+}
+
+// sendRawTransaction submits txn to the chain endpoint, or to the endpoint of its
+// kettle for confidential requests.
+func (c *Chain) sendRawTransaction(ctx context.Context, txn *types.Transaction) (common.Hash, error) {
+	txnBytes, err := txn.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	var hash common.Hash
+	if err := c.txRPC(txn).CallContext(ctx, &hash, "eth_sendRawTransaction", hexutil.Encode(txnBytes)); err != nil {
+		return common.Hash{}, err
+	}
+	return hash, nil
+}
+
+// TxURL returns the link to the transaction hash in the block explorer of the
+// chain, empty if no explorer is configured.
+func (c *Chain) TxURL(hash common.Hash) string {
+	if c.explorer == "" {
+		return ""
+	}
+	return strings.TrimRight(c.explorer, "/") + "/tx/" + hash.Hex()
+}
+
+// logTx logs the hash of a sent transaction, with its explorer link if any.
+func (c *Chain) logTx(hash common.Hash) {
+	if url := c.TxURL(hash); url != "" {
+		log.Printf("transaction hash: %s (%s)", hash.Hex(), url)
+		return
+	}
+	log.Printf("transaction hash: %s", hash.Hex())
+}
+
+// WaitForReceipt waits until the receipt of the transaction is available. It gives up
+// with a *ReceiptTimeoutError once the chain advances more than the configured number
+// of blocks without including the transaction, or when ctx is done.
+func (c *Chain) WaitForReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	startBlock, err := clt.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := clt.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+
+		if c.receiptBlocks != 0 {
+			current, err := clt.BlockNumber(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if current > startBlock+c.receiptBlocks {
+				return nil, &ReceiptTimeoutError{Hash: hash, Blocks: c.receiptBlocks}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, &ReceiptTimeoutError{Hash: hash, Err: ctx.Err()}
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package framework
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+// testFillAPI serves the "eth" methods used to fill a transaction.
+type testFillAPI struct {
+	nonce    uint64
+	gasPrice *big.Int
+	tip      *big.Int
+	baseFee  *big.Int
+	blobFee  *big.Int
+	gas      uint64
+}
+
+func (a *testFillAPI) GetTransactionCount(addr common.Address, block string) hexutil.Uint64 {
+	return hexutil.Uint64(a.nonce)
+}
+
+func (a *testFillAPI) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(a.gasPrice)
+}
+
+func (a *testFillAPI) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(a.tip)
+}
+
+func (a *testFillAPI) GetBlockByNumber(number string, full bool) *types.Header {
+	return &types.Header{Number: big.NewInt(1), Difficulty: common.Big0, BaseFee: a.baseFee}
+}
+
+func (a *testFillAPI) EstimateGas(args map[string]interface{}) hexutil.Uint64 {
+	return hexutil.Uint64(a.gas)
+}
+
+func (a *testFillAPI) BlobBaseFee() *hexutil.Big {
+	return (*hexutil.Big)(a.blobFee)
+}
+
+func newTestFillChain(t *testing.T, api *testFillAPI) *Chain {
+	t.Helper()
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", api))
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+
+	return &Chain{rpc: client, chainID: big.NewInt(1), nonces: newNonceManager()}
+}
+
+func TestFillTx(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	api := &testFillAPI{
+		nonce:    5,
+		gasPrice: big.NewInt(30),
+		tip:      big.NewInt(2),
+		baseFee:  big.NewInt(10),
+		blobFee:  big.NewInt(3),
+		gas:      21000,
+	}
+	chain := newTestFillChain(t, api)
+	nonce := uint64(7)
+
+	cases := []struct {
+		name  string
+		tx    types.TxData
+		nonce *uint64
+		want  types.TxData
+		err   string
+	}{
+		{
+			name:  "legacy",
+			nonce: &nonce,
+			tx:    &types.LegacyTx{To: &to},
+			want:  &types.LegacyTx{Nonce: 7, To: &to, GasPrice: big.NewInt(30), Gas: 21000},
+		},
+		{
+			name: "legacy with fields",
+			tx:   &types.LegacyTx{Nonce: 1, To: &to, GasPrice: big.NewInt(50), Gas: 30000},
+			want: &types.LegacyTx{Nonce: 1, To: &to, GasPrice: big.NewInt(50), Gas: 30000},
+		},
+		{
+			name:  "access list",
+			nonce: &nonce,
+			tx:    &types.AccessListTx{To: &to},
+			want:  &types.AccessListTx{ChainID: big.NewInt(1), Nonce: 7, To: &to, GasPrice: big.NewInt(30), Gas: 21000},
+		},
+		{
+			name:  "dynamic fee",
+			nonce: &nonce,
+			tx:    &types.DynamicFeeTx{To: &to},
+			want:  &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 7, To: &to, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(22), Gas: 21000},
+		},
+		{
+			name:  "dynamic fee with tip",
+			nonce: &nonce,
+			tx:    &types.DynamicFeeTx{ChainID: big.NewInt(5), To: &to, GasTipCap: big.NewInt(4)},
+			want:  &types.DynamicFeeTx{ChainID: big.NewInt(5), Nonce: 7, To: &to, GasTipCap: big.NewInt(4), GasFeeCap: big.NewInt(24), Gas: 21000},
+		},
+		{
+			name:  "suggested tip above the fee cap",
+			nonce: &nonce,
+			tx:    &types.DynamicFeeTx{To: &to, GasFeeCap: big.NewInt(1)},
+			want:  &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 7, To: &to, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000},
+		},
+		{
+			name:  "blob",
+			nonce: &nonce,
+			tx:    &types.BlobTx{To: &to, Value: uint256.NewInt(0)},
+			want:  &types.BlobTx{ChainID: uint256.NewInt(1), Nonce: 7, To: &to, Value: uint256.NewInt(0), GasTipCap: uint256.NewInt(2), GasFeeCap: uint256.NewInt(22), Gas: 21000, BlobFeeCap: uint256.NewInt(6)},
+		},
+		{
+			name: "confidential request",
+			tx:   &types.ConfidentialComputeRequest{},
+			err:  "cannot sign transactions of type",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			orig := types.NewTx(tc.tx)
+
+			got, err := chain.fillTx(context.Background(), to, tc.tx, tc.nonce)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+
+			// the transaction is not modified
+			require.Equal(t, orig.Hash(), types.NewTx(tc.tx).Hash())
+		})
+	}
+
+	t.Run("no base fee", func(t *testing.T) {
+		chain := newTestFillChain(t, &testFillAPI{tip: big.NewInt(2), gas: 21000})
+		_, err := chain.fillTx(context.Background(), to, &types.DynamicFeeTx{To: &to}, nil)
+		require.ErrorContains(t, err, "does not support EIP-1559 transactions")
+	})
+}
+
+func TestSignTx(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	acct := GeneratePrivKey()
+	tx := &types.DynamicFeeTx{To: &to, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(100), Gas: 21000}
+
+	t.Run("consecutive nonces", func(t *testing.T) {
+		chain := newTestFillChain(t, &testFillAPI{nonce: 5})
+
+		for _, want := range []uint64{5, 6, 7} {
+			signed, err := chain.SignTxE(context.Background(), acct, tx)
+			require.NoError(t, err)
+			require.Equal(t, want, signed.Nonce())
+
+			sender, err := types.Sender(chain.txSigner(), signed)
+			require.NoError(t, err)
+			require.Equal(t, acct.Address(), sender)
+		}
+	})
+
+	t.Run("explicit nonce", func(t *testing.T) {
+		chain := newTestFillChain(t, &testFillAPI{nonce: 5})
+
+		signed, err := chain.SignTxE(context.Background(), acct, tx, WithNonce(0))
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), signed.Nonce())
+
+		withNonce := *tx
+		withNonce.Nonce = 3
+		signed, err = chain.SignTxE(context.Background(), acct, &withNonce)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), signed.Nonce())
+
+		// the explicit nonces do not consume the ones of the account
+		signed, err = chain.SignTxE(context.Background(), acct, tx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), signed.Nonce())
+	})
+
+	t.Run("release an unsent nonce", func(t *testing.T) {
+		chain := newTestFillChain(t, &testFillAPI{nonce: 5})
+
+		signed, err := chain.SignTxE(context.Background(), acct, tx)
+		require.NoError(t, err)
+		chain.ReleaseNonce(acct.Address(), signed.Nonce())
+
+		signed, err = chain.SignTxE(context.Background(), acct, tx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), signed.Nonce())
+	})
+
+	t.Run("failure releases the nonce", func(t *testing.T) {
+		chain := newTestFillChain(t, &testFillAPI{nonce: 5, tip: big.NewInt(1)})
+
+		_, err := chain.SignTxE(context.Background(), acct, &types.DynamicFeeTx{To: &to, Gas: 21000})
+		require.Error(t, err)
+
+		signed, err := chain.SignTxE(context.Background(), acct, tx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), signed.Nonce())
+	})
+}
@@ -0,0 +1,61 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// VerifyResult checks that the SUAVE transaction hash is the result of the signed
+// confidential request: it wraps exactly the record and signature of the request,
+// and it is signed by the kettle the request was sent to, Framework.KettleAddress
+// unless it was selected with WithKettle. It returns a *ResultError otherwise.
+func (c *Chain) VerifyResult(ctx context.Context, request *types.Transaction, hash common.Hash) error {
+	clt, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+	result, _, err := clt.TransactionByHash(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to fetch transaction %s: %w", hash.Hex(), err)
+	}
+	signer, err := c.signer(ctx)
+	if err != nil {
+		return err
+	}
+	return verifyResult(signer, request, result)
+}
+
+func verifyResult(signer types.Signer, request, result *types.Transaction) error {
+	fail := func(format string, args ...interface{}) error {
+		return &ResultError{Hash: result.Hash(), Reason: fmt.Sprintf(format, args...)}
+	}
+
+	ccr, ok := types.CastTxInner[*types.ConfidentialComputeRequest](request)
+	if !ok {
+		return fmt.Errorf("transaction %s is not a confidential request", request.Hash().Hex())
+	}
+	suaveTx, ok := types.CastTxInner[*types.SuaveTransaction](result)
+	if !ok {
+		return fail("not a SUAVE transaction but type %d", result.Type())
+	}
+
+	record := types.NewTx(&suaveTx.ConfidentialComputeRequest)
+	if signer.Hash(record) != signer.Hash(request) {
+		return fail("wraps a different confidential request")
+	}
+	v, r, s := record.RawSignatureValues()
+	wantV, wantR, wantS := request.RawSignatureValues()
+	if v.Cmp(wantV) != 0 || r.Cmp(wantR) != 0 || s.Cmp(wantS) != 0 {
+		return fail("wraps a different signature of the confidential request")
+	}
+
+	// the sender of a SUAVE transaction is only recovered if the
+	// kettle of the request signed it
+	if _, err := types.Sender(signer, result); err != nil {
+		return fail("not signed by kettle %s: %v", ccr.KettleAddress.Hex(), err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyResult(t *testing.T) {
+	chainID := big.NewInt(16813125)
+	signer := types.NewSuaveSigner(chainID)
+	user, kettle, other := GeneratePrivKey(), GeneratePrivKey(), GeneratePrivKey()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	newRequest := func(t *testing.T, nonce uint64, eip712 bool) *types.Transaction {
+		t.Helper()
+
+		record := types.ConfidentialComputeRecord{
+			Nonce:                  nonce,
+			To:                     &to,
+			Gas:                    1_000_000,
+			GasPrice:               big.NewInt(1),
+			Data:                   []byte{0x01, 0x02},
+			KettleAddress:          kettle.Address(),
+			ConfidentialInputsHash: crypto.Keccak256Hash(nil),
+		}
+		if eip712 {
+			record.ChainID = chainID
+			record.IsEIP712 = true
+		}
+		request, err := user.SignTx(context.Background(), types.NewTx(&types.ConfidentialComputeRequest{ConfidentialComputeRecord: record}), chainID)
+		require.NoError(t, err)
+		return request
+	}
+	newResult := func(t *testing.T, request *types.Transaction, by *PrivKey, mutate func(*types.ConfidentialComputeRecord)) *types.Transaction {
+		t.Helper()
+
+		ccr, _ := types.CastTxInner[*types.ConfidentialComputeRequest](request)
+		record := ccr.ConfidentialComputeRecord
+		v, r, s := request.RawSignatureValues()
+		record.V, record.R, record.S = v, r, s
+		if mutate != nil {
+			mutate(&record)
+		}
+		result, err := types.SignTx(types.NewTx(&types.SuaveTransaction{
+			ConfidentialComputeRequest: record,
+			ConfidentialComputeResult:  []byte{0x12, 0x34, 0x56, 0x78},
+		}), signer, by.Priv)
+		require.NoError(t, err)
+		return result
+	}
+
+	request := newRequest(t, 1, false)
+	eip712Request := newRequest(t, 1, true)
+
+	cases := []struct {
+		name       string
+		request    *types.Transaction
+		result     *types.Transaction
+		wantErr    bool
+		wantResult bool
+	}{
+		{
+			name:    "valid",
+			request: request,
+			result:  newResult(t, request, kettle, nil),
+		},
+		{
+			name:    "valid EIP-712 request",
+			request: eip712Request,
+			result:  newResult(t, eip712Request, kettle, nil),
+		},
+		{
+			name:       "signed by another key",
+			request:    request,
+			result:     newResult(t, request, other, nil),
+			wantErr:    true,
+			wantResult: true,
+		},
+		{
+			name:       "different request",
+			request:    request,
+			result:     newResult(t, newRequest(t, 2, false), kettle, nil),
+			wantErr:    true,
+			wantResult: true,
+		},
+		{
+			name:    "different record",
+			request: request,
+			result: newResult(t, request, kettle, func(record *types.ConfidentialComputeRecord) {
+				record.Data = []byte{0x03}
+			}),
+			wantErr:    true,
+			wantResult: true,
+		},
+		{
+			name:    "different signature",
+			request: request,
+			result: newResult(t, request, kettle, func(record *types.ConfidentialComputeRecord) {
+				record.S = new(big.Int).Add(record.S, big.NewInt(1))
+			}),
+			wantErr:    true,
+			wantResult: true,
+		},
+		{
+			name:       "not a SUAVE transaction",
+			request:    request,
+			result:     types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Gas: 21000, GasPrice: big.NewInt(1)}),
+			wantErr:    true,
+			wantResult: true,
+		},
+		{
+			name:    "not a confidential request",
+			request: types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Gas: 21000, GasPrice: big.NewInt(1)}),
+			result:  newResult(t, request, kettle, nil),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifyResult(signer, tc.request, tc.result)
+			if !tc.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+
+			var resultErr *ResultError
+			require.Equal(t, tc.wantResult, errors.As(err, &resultErr))
+			if tc.wantResult {
+				require.ErrorIs(t, err, ErrInvalidResult)
+				require.Equal(t, tc.result.Hash(), resultErr.Hash)
+			}
+		})
+	}
+}